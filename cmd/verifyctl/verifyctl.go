@@ -35,12 +35,15 @@ func main() {
 		os.Exit(1)
 	}
 
-	config, err := config.NewCLIConfig().LoadFromFile()
+	cfg, err := config.NewCLIConfig().LoadFromFile()
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 
-	verifyCmd := cmd.NewRootCmd(config, nil)
+	vc := config.GetVerifyContext(ctx)
+	vc.Config = cfg
+
+	verifyCmd := cmd.NewRootCmd(cfg, nil)
 	cmdutil.ExitOnError(verifyCmd, verifyCmd.ExecuteContext(ctx))
 }
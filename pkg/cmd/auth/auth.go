@@ -2,10 +2,12 @@ package auth
 
 import (
 	"io"
+	"time"
 
 	"github.com/ibm-security-verify/verifyctl/pkg/config"
 	"github.com/ibm-security-verify/verifyctl/pkg/i18n"
 	"github.com/ibm-security-verify/verifyctl/pkg/module"
+	"github.com/ibm-security-verify/verifyctl/pkg/module/openapi"
 	cmdutil "github.com/ibm-security-verify/verifyctl/pkg/util/cmd"
 	"github.com/ibm-security-verify/verifyctl/pkg/util/templates"
 	"github.com/spf13/cobra"
@@ -116,7 +118,7 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 	vc := config.GetVerifyContext(ctx)
 
-	token := ""
+	var tokenResponse *openapi.TokenResponse
 	var authResource *AuthResource
 	var err error
 
@@ -136,15 +138,13 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if tokenResponse, err := o.authenticate(cmd, authResource); err != nil {
+	if tokenResponse, err = o.authenticate(cmd, authResource); err != nil {
 		vc.Logger.Warn("authentication failed", "client", authResource.ClientID, "err", err)
 		return err
-	} else {
-		token = tokenResponse.AccessToken
 	}
 
 	if o.printOnly {
-		cmdutil.WriteString(cmd, token)
+		cmdutil.WriteString(cmd, tokenResponse.AccessToken)
 		return nil
 	}
 
@@ -153,11 +153,22 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	o.config.AddAuth(&config.AuthConfig{
-		Tenant: o.tenant,
-		Token:  token,
-		User:   authResource.User,
-	})
+	authConfig := &config.AuthConfig{
+		Tenant:   o.tenant,
+		Token:    tokenResponse.AccessToken,
+		User:     authResource.User,
+		ClientID: authResource.ClientID,
+	}
+
+	if tokenResponse.RefreshToken != nil {
+		authConfig.RefreshToken = *tokenResponse.RefreshToken
+	}
+
+	if tokenResponse.ExpiresIn > 0 {
+		authConfig.ExpiresAt = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	o.config.AddAuth(authConfig)
 
 	// set current tenant
 	o.config.SetCurrentTenant(o.tenant)
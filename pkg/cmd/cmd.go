@@ -12,6 +12,7 @@ import (
 	"github.com/ibm-security-verify/verifyctl/pkg/config"
 	"github.com/ibm-security-verify/verifyctl/pkg/i18n"
 	cmdutil "github.com/ibm-security-verify/verifyctl/pkg/util/cmd"
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
 	"github.com/ibm-security-verify/verifyctl/pkg/util/templates"
 	"github.com/spf13/cobra"
 )
@@ -23,7 +24,7 @@ const (
 	debugGroupID    = "debug"
 )
 
-func NewRootCmd(config *config.CLIConfig, streams io.ReadWriter) *cobra.Command {
+func NewRootCmd(cliConfig *config.CLIConfig, streams io.ReadWriter) *cobra.Command {
 	// cmd represents the base command when called without any subcommands
 	cmd := &cobra.Command{
 		Use:   "verifyctl",
@@ -33,17 +34,38 @@ func NewRootCmd(config *config.CLIConfig, streams io.ReadWriter) *cobra.Command
   Find more information at: https://github.com/ibm-security-verify/verifyctl`)),
 	}
 
+	// keep the token of the current context fresh and save it if it was refreshed,
+	// unless it came from the environment
+	var currentAuth *config.AuthConfig
+	currentToken := ""
+	cmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if a, err := cliConfig.GetCurrentAuth(); err == nil {
+			currentAuth = a
+			currentToken = a.Token
+			cmd.SetContext(xhttp.ContextWithTokenSource(cmd.Context(), a))
+		}
+	}
+
+	cmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		if currentAuth != nil && !currentAuth.FromEnv() && currentAuth.Token != currentToken {
+			if _, err := cliConfig.PersistFile(); err != nil {
+				vc := config.GetVerifyContext(cmd.Context())
+				vc.Logger.Errorf("unable to save the refreshed token; err=%v", err)
+			}
+		}
+	}
+
 	cmd.SetOut(streams)
 	cmd.SetErr(streams)
 	cmd.SetIn(streams)
 
 	// add commands
-	cmd.AddCommand(auth.NewCommand(config, streams, basicGroupID))
-	cmd.AddCommand(get.NewCommand(config, streams, resourceGroupID))
-	cmd.AddCommand(create.NewCommand(config, streams, resourceGroupID))
-	cmd.AddCommand(replace.NewCommand(config, streams, resourceGroupID))
-	cmd.AddCommand(delete.NewCommand(config, streams, resourceGroupID))
-	cmd.AddCommand(logs.NewCommand(config, streams, debugGroupID))
+	cmd.AddCommand(auth.NewCommand(cliConfig, streams, basicGroupID))
+	cmd.AddCommand(get.NewCommand(cliConfig, streams, resourceGroupID))
+	cmd.AddCommand(create.NewCommand(cliConfig, streams, resourceGroupID))
+	cmd.AddCommand(replace.NewCommand(cliConfig, streams, resourceGroupID))
+	cmd.AddCommand(delete.NewCommand(cliConfig, streams, resourceGroupID))
+	cmd.AddCommand(logs.NewCommand(cliConfig, streams, debugGroupID))
 
 	// add groups
 	groups := []*cobra.Group{
@@ -1,6 +1,7 @@
 package delete
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/ibm-security-verify/verifyctl/pkg/config"
@@ -32,12 +33,16 @@ You can identify the entitlement required by running:
 
 	groupsExamples = templates.Examples(cmdutil.TranslateExamples(messagePrefix, `
 		# Delete a group
-		verifyctl delete group --displayName=Sales`,
+		verifyctl delete group --displayName=Sales
+
+		# Remove the members of a group and then delete it
+		verifyctl delete group --displayName=Sales --force`,
 	))
 )
 
 type groupsOptions struct {
 	options
+	force bool
 
 	config *config.CLIConfig
 }
@@ -72,6 +77,7 @@ func NewGroupCommand(config *config.CLIConfig, streams io.ReadWriter) *cobra.Com
 func (o *groupsOptions) AddFlags(cmd *cobra.Command) {
 	o.addCommonFlags(cmd)
 	cmd.Flags().StringVar(&o.name, "displayName", o.name, i18n.Translate("Group displayName to be deleted"))
+	cmd.Flags().BoolVar(&o.force, "force", o.force, i18n.Translate("Remove the members of the group before deleting it, for tenants that do not delete groups with members."))
 }
 
 func (o *groupsOptions) Complete(cmd *cobra.Command, args []string) error {
@@ -112,6 +118,15 @@ func (o *groupsOptions) Run(cmd *cobra.Command, args []string) error {
 func (o *groupsOptions) handleSingleGroup(cmd *cobra.Command, auth *config.AuthConfig, _ []string) error {
 
 	c := directory.NewGroupClient()
+	if o.force {
+		removed, err := c.ForceDeleteGroup(cmd.Context(), auth, o.name)
+		if err != nil {
+			return err
+		}
+		cmdutil.WriteString(cmd, fmt.Sprintf("Resource deleted: %s (%d members removed)", o.name, removed))
+		return nil
+	}
+
 	err := c.DeleteGroup(cmd.Context(), auth, o.name)
 	if err != nil {
 		return err
@@ -49,6 +49,7 @@ type options struct {
 	page         int
 	sort         string
 	search       string
+	filter       string
 	count        string
 	//properties   string
 	id   string
@@ -111,6 +112,10 @@ func (o *options) addSearchFlags(cmd *cobra.Command, _ string) {
 	cmd.Flags().StringVar(&o.search, "search", "", i18n.Translate("Specify the search criteria to fetch lists."))
 }
 
+func (o *options) addFilterFlags(cmd *cobra.Command, _ string) {
+	cmd.Flags().StringVar(&o.filter, "filter", "", i18n.Translate("Specify the SCIM filter expression to fetch lists."))
+}
+
 func (o *options) addCountFlags(cmd *cobra.Command, _ string) {
 	cmd.Flags().StringVar(&o.count, "count", "", i18n.Translate("Specify the count to fetch lists."))
 }
@@ -1,6 +1,7 @@
 package get
 
 import (
+	"encoding/json"
 	"io"
 
 	"github.com/ibm-security-verify/verifyctl/pkg/cmd/resource"
@@ -36,7 +37,14 @@ You can identify the entitlement required by running:
 		verifyctl get group -o=yaml --displayName=admin
 
 		# Get 10 groups based on a given search criteria and sort it in the ascending order by name.
-		verifyctl get groups --count=2 --sort=groupName -o=yaml`))
+		verifyctl get groups --count=2 --sort=groupName -o=yaml
+
+		# List groups as a table, adding the description with the wide variant.
+		verifyctl get groups -o=table
+		verifyctl get groups -o=wide
+
+		# Get groups that have been modified after a given time.
+		verifyctl get groups --filter="meta.lastModified gt \"2024-01-01T00:00:00Z\"" -o=yaml`))
 )
 
 type groupsOptions struct {
@@ -75,7 +83,9 @@ func NewGroupsCommand(config *config.CLIConfig, streams io.ReadWriter) *cobra.Co
 
 func (o *groupsOptions) AddFlags(cmd *cobra.Command) {
 	o.addCommonFlags(cmd, groupResourceName)
+	cmd.Flags().Lookup("output").Usage = i18n.Translate("Select the format of the output. The values supported are 'json', 'yaml', 'raw', 'table' and 'wide'. Default: 'json'.")
 	cmd.Flags().StringVar(&o.name, "displayName", o.name, i18n.Translate("Group displayName to get details"))
+	o.addFilterFlags(cmd, groupResourceName)
 	o.addSortFlags(cmd, groupResourceName)
 	o.addCountFlags(cmd, groupResourceName)
 }
@@ -124,9 +134,29 @@ func (o *groupsOptions) handleSingleGroup(cmd *cobra.Command, auth *config.AuthC
 		return err
 	}
 
-	if o.output == "raw" {
-		cmdutil.WriteAsJSON(cmd, grp, cmd.OutOrStdout())
+	switch o.output {
+	case "raw":
+		b, err := directory.FormatGroup(grp, directory.OutputFormatJSON)
+		if err != nil {
+			return err
+		}
+		cmdutil.WriteAsBinary(cmd, b, cmd.OutOrStdout())
 		return nil
+	case directory.OutputFormatTable, directory.OutputFormatWide:
+		b, err := directory.FormatGroupList(&directory.GroupListResponse{
+			TotalResults: 1,
+			Groups:       []directory.Group{*grp},
+		}, o.output)
+		if err != nil {
+			return err
+		}
+		cmdutil.WriteAsBinary(cmd, b, cmd.OutOrStdout())
+		return nil
+	}
+
+	data, err := groupData(grp)
+	if err != nil {
+		return err
 	}
 
 	resourceObj := &resource.ResourceObject{
@@ -136,7 +166,7 @@ func (o *groupsOptions) handleSingleGroup(cmd *cobra.Command, auth *config.AuthC
 			Name: grp.DisplayName,
 			URI:  uri,
 		},
-		Data: grp,
+		Data: data,
 	}
 
 	if o.output == "json" {
@@ -151,25 +181,43 @@ func (o *groupsOptions) handleSingleGroup(cmd *cobra.Command, auth *config.AuthC
 func (o *groupsOptions) handleGroupList(cmd *cobra.Command, auth *config.AuthConfig, _ []string) error {
 
 	c := directory.NewGroupClient()
-	grps, uri, err := c.GetGroups(cmd.Context(), auth, o.sort, o.count)
+	grps, uri, err := c.GetGroups(cmd.Context(), auth, o.filter, o.sort, "", o.count, "")
 	if err != nil {
 		return err
 	}
 
-	if o.output == "raw" {
-		cmdutil.WriteAsJSON(cmd, grps, cmd.OutOrStdout())
+	switch o.output {
+	case "raw":
+		b, err := directory.FormatGroupList(grps, directory.OutputFormatJSON)
+		if err != nil {
+			return err
+		}
+		cmdutil.WriteAsBinary(cmd, b, cmd.OutOrStdout())
+		return nil
+	case directory.OutputFormatTable, directory.OutputFormatWide:
+		b, err := directory.FormatGroupList(grps, o.output)
+		if err != nil {
+			return err
+		}
+		cmdutil.WriteAsBinary(cmd, b, cmd.OutOrStdout())
 		return nil
 	}
 
 	items := []*resource.ResourceObject{}
-	for _, grp := range grps.Groups {
+	for i := range grps.Groups {
+		grp := &grps.Groups[i]
+		data, err := groupData(grp)
+		if err != nil {
+			return err
+		}
+
 		items = append(items, &resource.ResourceObject{
 			Kind:       resource.ResourceTypePrefix + "Group",
 			APIVersion: "2.0",
 			Metadata: &resource.ResourceObjectMetadata{
 				Name: grp.DisplayName,
 			},
-			Data: grp,
+			Data: data,
 		})
 	}
 
@@ -191,3 +239,20 @@ func (o *groupsOptions) handleGroupList(cmd *cobra.Command, auth *config.AuthCon
 
 	return nil
 }
+
+// groupData returns the group as rendered by directory.FormatGroup, so that the
+// YAML output carries the schema extensions and omits empty ones just as the JSON
+// output does.
+func groupData(grp *directory.Group) (interface{}, error) {
+	b, err := directory.FormatGroup(grp, directory.OutputFormatJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -76,6 +76,7 @@ func NewUsersCommand(config *config.CLIConfig, streams io.ReadWriter) *cobra.Com
 func (o *usersOptions) AddFlags(cmd *cobra.Command) {
 	o.addCommonFlags(cmd, userResourceName)
 	cmd.Flags().StringVar(&o.name, "userName", o.name, i18n.Translate("userName to get details"))
+	o.addFilterFlags(cmd, userResourceName)
 	o.addSortFlags(cmd, userResourceName)
 	o.addCountFlags(cmd, userResourceName)
 }
@@ -152,7 +153,7 @@ func (o *usersOptions) handleSingleUser(cmd *cobra.Command, auth *config.AuthCon
 func (o *usersOptions) handleUserList(cmd *cobra.Command, auth *config.AuthConfig, _ []string) error {
 
 	c := directory.NewUserClient()
-	usrs, uri, err := c.GetUsers(cmd.Context(), auth, o.sort, o.count)
+	usrs, uri, err := c.GetUsers(cmd.Context(), auth, o.filter, o.sort, o.count, "")
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	cmdutil "github.com/ibm-security-verify/verifyctl/pkg/util/cmd"
 	"gopkg.in/yaml.v3"
@@ -15,19 +18,45 @@ const (
 	kind        = "Config"
 	fileName    = "config"
 	defaultPerm = os.ModePerm
+
+	// EnvToken and EnvTenant hold credentials that take precedence over the stored
+	// ones, such as a token injected by a secret manager in a pipeline.
+	EnvToken  = "VERIFY_TOKEN"
+	EnvTenant = "VERIFY_TENANT"
 )
 
 type CLIConfig struct {
-	APIVersion    string        `yaml:"apiVersion"`
-	Kind          string        `yaml:"kind"`
-	CurrentTenant string        `yaml:"tenant"`
-	Auth          []*AuthConfig `yaml:"auth"`
+	APIVersion     string        `yaml:"apiVersion"`
+	Kind           string        `yaml:"kind"`
+	CurrentTenant  string        `yaml:"tenant"`
+	CurrentContext string        `yaml:"currentContext,omitempty"`
+	Auth           []*AuthConfig `yaml:"auth"`
+
+	// env holds the credentials taken from the environment.
+	env *AuthConfig
 }
 
 type AuthConfig struct {
+	// Name identifies the auth config as a context, such as "dev" or "prod".
+	// When it is not set, the tenant is used as the context name.
+	Name   string `yaml:"name,omitempty"`
 	Tenant string `yaml:"tenant"`
 	Token  string `yaml:"token"`
 	User   bool   `yaml:"isUser"`
+
+	// BaseURL replaces https://<tenant> as the root of the API URLs, such as
+	// http://127.0.0.1:8080 for a local mock server.
+	BaseURL string `yaml:"baseUrl,omitempty"`
+
+	// ClientID and RefreshToken are used to get a new access token when the
+	// current one expires.
+	ClientID     string    `yaml:"clientId,omitempty"`
+	RefreshToken string    `yaml:"refreshToken,omitempty"`
+	ExpiresAt    time.Time `yaml:"expiresAt,omitempty"`
+
+	mu         sync.Mutex
+	refreshing *refreshCall
+	fromEnv    bool
 }
 
 func NewCLIConfig() *CLIConfig {
@@ -41,7 +70,7 @@ func NewCLIConfig() *CLIConfig {
 func (o *CLIConfig) AddAuth(config *AuthConfig) {
 	// check if it already exists and replace if so
 	for _, c := range o.Auth {
-		if c.Tenant == config.Tenant {
+		if c.ContextName() == config.ContextName() {
 			// replace
 			c.Merge(config)
 			return
@@ -52,8 +81,60 @@ func (o *CLIConfig) AddAuth(config *AuthConfig) {
 	o.Auth = append(o.Auth, config)
 }
 
+// envAuth returns the credentials set in the environment, or nil if VERIFY_TOKEN is
+// not set. The same AuthConfig is returned on every call.
+func (o *CLIConfig) envAuth() (*AuthConfig, error) {
+	token := os.Getenv(EnvToken)
+	if len(token) == 0 {
+		return nil, nil
+	}
+
+	tenant := os.Getenv(EnvTenant)
+	if len(tenant) == 0 {
+		tenant = o.CurrentTenant
+	}
+
+	if len(tenant) == 0 {
+		return nil, fmt.Errorf("%s is set but no tenant is; set %s or the current tenant", EnvToken, EnvTenant)
+	}
+
+	if o.env == nil || o.env.Token != token || o.env.Tenant != tenant {
+		o.env = &AuthConfig{
+			Tenant:  tenant,
+			Token:   token,
+			fromEnv: true,
+		}
+	}
+
+	return o.env, nil
+}
+
 func (o *CLIConfig) SetCurrentTenant(tenant string) {
 	o.CurrentTenant = tenant
+	o.CurrentContext = ""
+}
+
+// ListContexts returns the names of the stored contexts.
+func (o *CLIConfig) ListContexts() []string {
+	names := []string{}
+	for _, c := range o.Auth {
+		names = append(names, c.ContextName())
+	}
+
+	return names
+}
+
+// SetCurrentContext makes the named context the active one.
+func (o *CLIConfig) SetCurrentContext(name string) error {
+	for _, c := range o.Auth {
+		if c.ContextName() == name {
+			o.CurrentContext = name
+			o.CurrentTenant = c.Tenant
+			return nil
+		}
+	}
+
+	return fmt.Errorf("context %s does not exist", name)
 }
 
 func (o *CLIConfig) LoadFromFile() (*CLIConfig, error) {
@@ -99,7 +180,27 @@ func (o *CLIConfig) PersistFile() (*CLIConfig, error) {
 	return o, nil
 }
 
+// GetCurrentAuth returns the credentials to use, which are taken from the first of:
+//
+//  1. the VERIFY_TOKEN environment variable, for the tenant in VERIFY_TENANT or,
+//     if it is not set, the current tenant
+//  2. the current context
+//  3. the stored login for the current tenant
+//
+// Credentials from the environment are never written to the config file.
 func (o *CLIConfig) GetCurrentAuth() (*AuthConfig, error) {
+	if auth, err := o.envAuth(); auth != nil || err != nil {
+		return auth, err
+	}
+
+	if len(o.CurrentContext) > 0 {
+		for _, c := range o.Auth {
+			if c.ContextName() == o.CurrentContext {
+				return c, nil
+			}
+		}
+	}
+
 	for _, c := range o.Auth {
 		if c.Tenant == o.CurrentTenant {
 			return c, nil
@@ -109,8 +210,38 @@ func (o *CLIConfig) GetCurrentAuth() (*AuthConfig, error) {
 	return nil, fmt.Errorf("No login session available. Use:\n  verifyctl login -h")
 }
 
+// ContextName returns the name of the context, which defaults to the tenant.
+func (o *AuthConfig) ContextName() string {
+	if len(o.Name) > 0 {
+		return o.Name
+	}
+
+	return o.Tenant
+}
+
+// FromEnv reports whether the credentials were taken from the environment, in which
+// case they must not be written to the config file.
+func (o *AuthConfig) FromEnv() bool {
+	return o.fromEnv
+}
+
+// TenantURL returns the URL of the path on the tenant, rooted at the BaseURL if one
+// is set and https://<tenant> otherwise.
+func (o *AuthConfig) TenantURL(path ...string) string {
+	base := strings.TrimSuffix(o.BaseURL, "/")
+	if len(base) == 0 {
+		base = fmt.Sprintf("https://%s", o.Tenant)
+	}
+
+	return strings.Join(append([]string{base}, path...), "/")
+}
+
 func (o *AuthConfig) Merge(c *AuthConfig) {
+	o.Name = c.Name
 	o.Tenant = c.Tenant
 	o.Token = c.Token
 	o.User = c.User
+	o.ClientID = c.ClientID
+	o.RefreshToken = c.RefreshToken
+	o.ExpiresAt = c.ExpiresAt
 }
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestGetCurrentAuthFromEnv(t *testing.T) {
+	stored := &AuthConfig{
+		Tenant: "tenant.example.com",
+		Token:  "stored",
+	}
+	cliConfig := NewCLIConfig()
+	cliConfig.AddAuth(stored)
+	cliConfig.SetCurrentTenant(stored.Tenant)
+
+	auth, err := cliConfig.GetCurrentAuth()
+	if err != nil || auth != stored || auth.FromEnv() {
+		t.Fatalf("GetCurrentAuth() = %+v, %v; want the stored login", auth, err)
+	}
+
+	t.Setenv(EnvToken, "env")
+	auth, err = cliConfig.GetCurrentAuth()
+	if err != nil {
+		t.Fatalf("GetCurrentAuth() err = %v, want nil", err)
+	}
+
+	if auth.Token != "env" || auth.Tenant != stored.Tenant || !auth.FromEnv() {
+		t.Errorf("GetCurrentAuth() = %q for %q, FromEnv() = %t; want env for %q, true", auth.Token, auth.Tenant, auth.FromEnv(), stored.Tenant)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ibm-security-verify/verifyctl/x/logx"
 )
@@ -15,6 +16,7 @@ const (
 
 type VerifyContext struct {
 	Logger *logx.Logger
+	Config *CLIConfig
 }
 
 func NewContextWithVerifyContext(parentContext context.Context, logger *logx.Logger) (context.Context, error) {
@@ -30,3 +32,22 @@ func GetVerifyContext(ctx context.Context) *VerifyContext {
 	vc, _ := ctx.Value(VerifyCtxKey).(*VerifyContext)
 	return vc
 }
+
+// GetLogger returns the logger from the verify context, or nil if there is none.
+func GetLogger(ctx context.Context) *logx.Logger {
+	if vc := GetVerifyContext(ctx); vc != nil {
+		return vc.Logger
+	}
+
+	return nil
+}
+
+// CurrentAuth resolves the auth config of the active context from the CLI config
+// attached to the verify context.
+func (vc *VerifyContext) CurrentAuth() (*AuthConfig, error) {
+	if vc.Config == nil {
+		return nil, fmt.Errorf("no configuration is loaded")
+	}
+
+	return vc.Config.GetCurrentAuth()
+}
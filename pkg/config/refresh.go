@@ -0,0 +1,136 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// refreshLeeway is how long before expiry the access token is refreshed.
+	refreshLeeway = 30 * time.Second
+)
+
+// refreshCall is a refresh in flight, which other callers wait for.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+var (
+	// ErrAuthExpired is returned when the access token has expired and could not be refreshed.
+	ErrAuthExpired = errors.New("the session has expired; login again")
+)
+
+// AccessToken returns the access token, refreshing it first if it is about to expire.
+// Without a refresh token, ErrAuthExpired is returned once the token has expired so
+// that no request is sent with it.
+func (o *AuthConfig) AccessToken(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	token := o.Token
+	expiring := !o.ExpiresAt.IsZero() && time.Until(o.ExpiresAt) < refreshLeeway
+	canRefresh := len(o.RefreshToken) > 0
+	o.mu.Unlock()
+
+	if !expiring {
+		return token, nil
+	}
+
+	if !canRefresh {
+		if o.Expired() {
+			return "", ErrAuthExpired
+		}
+
+		return token, nil
+	}
+
+	return o.Refresh(ctx, token)
+}
+
+// Expired reports whether the access token is known to have expired. A token
+// without an expiry time is assumed to be valid.
+func (o *AuthConfig) Expired() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return !o.ExpiresAt.IsZero() && !time.Now().Before(o.ExpiresAt)
+}
+
+// Refresh exchanges the refresh token for a new access token that replaces staleToken.
+// If another caller has already replaced staleToken, the current token is returned
+// without making another call, and callers that arrive while a refresh is in flight
+// wait for its result, so concurrent requests trigger a single refresh. The lock is
+// not held during the exchange. The token endpoint is called with the *http.Client
+// in the context under oauth2.HTTPClient, if there is one.
+//
+// The refreshed values are only held in memory; callers should persist the config
+// to keep them for later sessions.
+func (o *AuthConfig) Refresh(ctx context.Context, staleToken string) (string, error) {
+	o.mu.Lock()
+	for o.refreshing != nil {
+		call := o.refreshing
+		o.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+
+		if call.err != nil {
+			return "", call.err
+		}
+
+		o.mu.Lock()
+	}
+
+	if o.Token != staleToken {
+		token := o.Token
+		o.mu.Unlock()
+		return token, nil
+	}
+
+	if len(o.RefreshToken) == 0 {
+		o.mu.Unlock()
+		return "", ErrAuthExpired
+	}
+
+	call := &refreshCall{
+		done: make(chan struct{}),
+	}
+	o.refreshing = call
+	oauthConfig := &oauth2.Config{
+		ClientID: o.ClientID,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  o.TenantURL("oauth2", "token"),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+	refreshToken := o.RefreshToken
+	o.mu.Unlock()
+
+	token, err := oauthConfig.TokenSource(ctx, &oauth2.Token{
+		RefreshToken: refreshToken,
+	}).Token()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	defer close(call.done)
+	o.refreshing = nil
+
+	if err != nil {
+		call.err = fmt.Errorf("%w; err=%v", ErrAuthExpired, err)
+		return "", call.err
+	}
+
+	o.Token = token.AccessToken
+	o.ExpiresAt = token.Expiry
+	if len(token.RefreshToken) > 0 {
+		o.RefreshToken = token.RefreshToken
+	}
+
+	return o.Token, nil
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// countingTransport counts the requests it sends.
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestRefreshSingleFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"fresh","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	auth := &AuthConfig{
+		Tenant:       "tenant.example.com",
+		Token:        "stale",
+		RefreshToken: "refresh",
+		BaseURL:      server.URL,
+	}
+
+	transport := &countingTransport{}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: transport})
+
+	const callers = 5
+	tokens := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = auth.Refresh(ctx, "stale")
+		}(i)
+	}
+
+	<-started
+
+	// the lock is not held while the token is exchanged
+	expired := make(chan bool)
+	go func() {
+		expired <- auth.Expired()
+	}()
+	select {
+	case <-expired:
+	case <-time.After(time.Second):
+		t.Fatal("Expired() blocked while the token was refreshed")
+	}
+
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		if tokens[i] != "fresh" || errs[i] != nil {
+			t.Errorf("Refresh() = %q, %v; want fresh, nil", tokens[i], errs[i])
+		}
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("token endpoint called %d times, want 1", got)
+	}
+
+	if got := transport.requests.Load(); got != 1 {
+		t.Errorf("client in the context sent %d requests, want 1", got)
+	}
+}
+
+func TestRefreshFailureKeepsToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+	defer server.Close()
+
+	auth := &AuthConfig{
+		Tenant:       "tenant.example.com",
+		Token:        "stale",
+		RefreshToken: "refresh",
+		BaseURL:      server.URL,
+	}
+
+	if _, err := auth.Refresh(context.Background(), "stale"); !errors.Is(err, ErrAuthExpired) {
+		t.Errorf("Refresh() err = %v, want %v", err, ErrAuthExpired)
+	}
+
+	if auth.Token != "stale" {
+		t.Errorf("Token = %q, want stale", auth.Token)
+	}
+}
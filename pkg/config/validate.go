@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+// AuthStatus is the state of the stored credentials, as reported by ValidateAuth.
+type AuthStatus string
+
+const (
+	// AuthValid means the tenant accepted the token.
+	AuthValid AuthStatus = "valid"
+
+	// AuthExpired means the token has expired or was revoked and could not be
+	// refreshed.
+	AuthExpired AuthStatus = "expired"
+
+	// AuthUnauthorized means the token is valid but lacks the entitlements to read
+	// the directory.
+	AuthUnauthorized AuthStatus = "unauthorized"
+)
+
+var (
+	// ErrAuthUnauthorized is returned when the token is valid but is not allowed to
+	// make the request.
+	ErrAuthUnauthorized = errors.New("the token is not allowed to make this request; check the client or application entitlements")
+)
+
+// ValidateAuth checks the credentials by asking the tenant for the number of
+// groups, which is cheap and allowed for most clients. The token is refreshed
+// first if it is about to expire. ErrAuthExpired or ErrAuthUnauthorized is
+// returned with the matching status; any other failure is returned on its own.
+func ValidateAuth(ctx context.Context, auth *AuthConfig) (AuthStatus, error) {
+	vc := GetVerifyContext(ctx)
+	token, err := auth.AccessToken(ctx)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the access token; err=%s", err.Error())
+		return AuthExpired, err
+	}
+
+	u, err := url.Parse(auth.TenantURL("v2.0", "Groups"))
+	if err != nil {
+		vc.Logger.Errorf("unable to parse the tenant URL; err=%s", err.Error())
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("count", "0")
+	q.Set("attributes", "id")
+	u.RawQuery = q.Encode()
+	headers := http.Header{
+		"Accept":        []string{"application/scim+json"},
+		"Authorization": []string{"Bearer " + token},
+	}
+
+	response, err := xhttp.NewDefaultClient().Get(xhttp.ContextWithTokenSource(ctx, auth), u, headers)
+	if err != nil {
+		if errors.Is(err, ErrAuthExpired) {
+			return AuthExpired, err
+		}
+
+		vc.Logger.Errorf("unable to validate the credentials; err=%s", err.Error())
+		return "", err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return AuthValid, nil
+	case http.StatusUnauthorized:
+		return AuthExpired, ErrAuthExpired
+	case http.StatusForbidden:
+		return AuthUnauthorized, ErrAuthUnauthorized
+	}
+
+	vc.Logger.Errorf("unable to validate the credentials; code=%d, body=%s", response.StatusCode, string(response.Body))
+	return "", fmt.Errorf("unable to validate the credentials; code=%d", response.StatusCode)
+}
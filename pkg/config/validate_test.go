@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+func TestValidateAuthUsesBaseURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   int
+		status AuthStatus
+		err    error
+	}{
+		{name: "valid", code: http.StatusOK, status: AuthValid},
+		{name: "expired", code: http.StatusUnauthorized, status: AuthExpired, err: ErrAuthExpired},
+		{name: "unauthorized", code: http.StatusForbidden, status: AuthUnauthorized, err: ErrAuthUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path, authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				authorization = r.Header.Get("Authorization")
+				w.WriteHeader(tt.code)
+			}))
+			defer server.Close()
+
+			ctx, err := NewContextWithVerifyContext(context.Background(), logx.NewLoggerWithWriter("test", slog.LevelError, io.Discard))
+			if err != nil {
+				t.Fatalf("unable to create the context; err=%v", err)
+			}
+
+			auth := &AuthConfig{
+				Tenant:  "tenant.example.com",
+				Token:   "token",
+				BaseURL: server.URL + "/",
+			}
+
+			status, err := ValidateAuth(ctx, auth)
+			if status != tt.status || err != tt.err {
+				t.Errorf("ValidateAuth() = %q, %v; want %q, %v", status, err, tt.status, tt.err)
+			}
+
+			if path != "/v2.0/Groups" || authorization != "Bearer token" {
+				t.Errorf("request path = %q, authorization = %q; want /v2.0/Groups, Bearer token", path, authorization)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+var (
+	// ErrIntrospectionUnsupported is returned when the tenant does not allow the
+	// stored client to introspect the token and the token has no user to look up.
+	ErrIntrospectionUnsupported = errors.New("the token cannot be introspected by this client; login with a client that is allowed to introspect tokens")
+)
+
+// TokenInfo describes the identity and permissions carried by an access token.
+type TokenInfo struct {
+	Subject   string    `json:"subject" yaml:"subject"`
+	ClientID  string    `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	Scopes    []string  `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+}
+
+type introspectResponse struct {
+	Active   bool   `json:"active"`
+	Subject  string `json:"sub"`
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"`
+	Expiry   int64  `json:"exp"`
+}
+
+type userInfoResponse struct {
+	Subject string `json:"sub"`
+}
+
+// Whoami returns the subject, client, scopes and expiry of the access token by
+// introspecting it with the stored client ID. If the tenant does not allow the
+// client to introspect tokens, the subject of a user login is read from the
+// userinfo endpoint instead, and ErrIntrospectionUnsupported is returned for
+// other logins.
+func Whoami(ctx context.Context, auth *AuthConfig) (*TokenInfo, error) {
+	vc := GetVerifyContext(ctx)
+	token, err := auth.AccessToken(ctx)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the access token; err=%s", err.Error())
+		return nil, err
+	}
+
+	client := xhttp.NewDefaultClient()
+	u, _ := url.Parse(fmt.Sprintf("https://%s/oauth2/introspect", auth.Tenant))
+	headers := http.Header{
+		"Accept":       []string{"application/json"},
+		"Content-Type": []string{"application/x-www-form-urlencoded"},
+	}
+	form := url.Values{
+		"token":     []string{token},
+		"client_id": []string{auth.ClientID},
+	}
+
+	response, err := client.Post(ctx, u, headers, []byte(form.Encode()))
+	if err != nil {
+		vc.Logger.Errorf("unable to introspect the token; err=%s", err.Error())
+		return nil, err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		introspection := &introspectResponse{}
+		if err := json.Unmarshal(response.Body, introspection); err != nil {
+			vc.Logger.Errorf("unable to parse the introspection response; err=%s", err.Error())
+			return nil, fmt.Errorf("unable to parse the introspection response")
+		}
+
+		if !introspection.Active {
+			return nil, ErrAuthExpired
+		}
+
+		info := &TokenInfo{
+			Subject:  introspection.Subject,
+			ClientID: introspection.ClientID,
+			Scopes:   strings.Fields(introspection.Scope),
+		}
+		if introspection.Expiry > 0 {
+			info.ExpiresAt = time.Unix(introspection.Expiry, 0).UTC()
+		}
+
+		return info, nil
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		vc.Logger.Debugf("unable to introspect the token; code=%d, body=%s", response.StatusCode, string(response.Body))
+	default:
+		vc.Logger.Errorf("unable to introspect the token; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return nil, fmt.Errorf("unable to introspect the token; code=%d", response.StatusCode)
+	}
+
+	if !auth.User {
+		return nil, ErrIntrospectionUnsupported
+	}
+
+	return userInfo(xhttp.ContextWithTokenSource(ctx, auth), client, auth, token)
+}
+
+// userInfo returns the subject of a user token from the userinfo endpoint.
+func userInfo(ctx context.Context, client xhttp.Clientx, auth *AuthConfig, token string) (*TokenInfo, error) {
+	vc := GetVerifyContext(ctx)
+	u, _ := url.Parse(fmt.Sprintf("https://%s/oauth2/userinfo", auth.Tenant))
+	headers := http.Header{
+		"Accept":        []string{"application/json"},
+		"Authorization": []string{"Bearer " + token},
+	}
+
+	response, err := client.Get(ctx, u, headers)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the user info; err=%s", err.Error())
+		return nil, err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, ErrAuthExpired
+	case http.StatusForbidden:
+		return nil, ErrIntrospectionUnsupported
+	default:
+		vc.Logger.Errorf("unable to get the user info; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return nil, fmt.Errorf("unable to get the user info; code=%d", response.StatusCode)
+	}
+
+	userInfo := &userInfoResponse{}
+	if err := json.Unmarshal(response.Body, userInfo); err != nil {
+		vc.Logger.Errorf("unable to parse the user info; err=%s", err.Error())
+		return nil, fmt.Errorf("unable to parse the user info")
+	}
+
+	return &TokenInfo{
+		Subject:   userInfo.Subject,
+		ClientID:  auth.ClientID,
+		ExpiresAt: auth.ExpiresAt,
+	}, nil
+}
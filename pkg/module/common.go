@@ -6,9 +6,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 
 	"github.com/ibm-security-verify/verifyctl/pkg/module/openapi"
 	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+const (
+	scimErrorSchema = "urn:ietf:params:scim:api:messages:2.0:Error"
 )
 
 type VerifyError struct {
@@ -16,31 +22,120 @@ type VerifyError struct {
 	MessageDescription string `json:"messageDescription" yaml:"messageDescription"`
 }
 
+// SCIMError is the standard error body returned by SCIM endpoints.
+type SCIMError struct {
+	Schemas  []string `json:"schemas" yaml:"schemas"`
+	Status   string   `json:"status" yaml:"status"`
+	ScimType string   `json:"scimType,omitempty" yaml:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty" yaml:"detail,omitempty"`
+}
+
+// UnmarshalJSON accepts the status as a string, as RFC 7644 specifies, or as the
+// number some tenants send.
+func (e *SCIMError) UnmarshalJSON(data []byte) error {
+	type scimError SCIMError
+	raw := struct {
+		*scimError
+		Status json.RawMessage `json:"status"`
+	}{
+		scimError: (*scimError)(e),
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.Status = ""
+	if len(raw.Status) == 0 || string(raw.Status) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw.Status, &e.Status); err == nil {
+		return nil
+	}
+
+	var status json.Number
+	if err := json.Unmarshal(raw.Status, &status); err != nil {
+		return fmt.Errorf("invalid SCIM error status %s", raw.Status)
+	}
+
+	e.Status = status.String()
+	return nil
+}
+
+func (e *SCIMError) Error() string {
+	if len(e.ScimType) == 0 {
+		return e.Detail
+	}
+
+	return fmt.Sprintf("%s: %s", e.ScimType, e.Detail)
+}
+
+// ParseSCIMError parses the body as a SCIM error. It returns false if the body is
+// not a SCIM error.
+func ParseSCIMError(body []byte) (*SCIMError, bool) {
+	scimError := &SCIMError{}
+	if err := json.Unmarshal(body, scimError); err != nil {
+		return nil, false
+	}
+
+	if !slices.Contains(scimError.Schemas, scimErrorSchema) || (len(scimError.Detail) == 0 && len(scimError.ScimType) == 0) {
+		return nil, false
+	}
+
+	scimError.Detail = logx.Redact(scimError.Detail)
+	return scimError, true
+}
+
+// HandleCommonErrors returns an APIError for the status codes that are handled the
+// same way by every API, or for any other error response with a SCIM error body. It
+// returns nil otherwise.
 func HandleCommonErrors(ctx context.Context, response *xhttp.Response, defaultError string) error {
+	if err := handleCommonErrors(response, defaultError); err != nil {
+		return NewAPIError(response, err)
+	}
+
+	return nil
+}
+
+func handleCommonErrors(response *xhttp.Response, defaultError string) error {
 	if response.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("Login again.")
+		return ErrUnauthorized
 	}
 
 	if response.StatusCode == http.StatusForbidden {
-		return fmt.Errorf("You are not allowed to make this request. Check the client or application entitlements.")
+		return ErrForbidden
 	}
 
 	if response.StatusCode == http.StatusBadRequest {
+		if scimError, ok := ParseSCIMError(response.Body); ok {
+			return scimError
+		}
+
 		var errorMessage VerifyError
 		if err := json.Unmarshal(response.Body, &errorMessage); err != nil {
 			return fmt.Errorf("bad request: %s", defaultError)
 		}
 		// If the expected fields are not populated, return the raw response body.
 		if errorMessage.MessageID == "" && errorMessage.MessageDescription == "" {
-			return fmt.Errorf("bad request: %s", string(response.Body))
+			return fmt.Errorf("bad request: %s", logx.Redact(string(response.Body)))
 		}
-		return fmt.Errorf("%s %s", errorMessage.MessageID, errorMessage.MessageDescription)
+		return fmt.Errorf("%s %s", errorMessage.MessageID, logx.Redact(errorMessage.MessageDescription))
 	}
 
 	if response.StatusCode == http.StatusNotFound {
+		if scimError, ok := ParseSCIMError(response.Body); ok {
+			return fmt.Errorf("Resource not found: %w", scimError)
+		}
+
 		return fmt.Errorf("Resource not found")
 	}
 
+	if response.StatusCode >= http.StatusBadRequest {
+		if scimError, ok := ParseSCIMError(response.Body); ok {
+			return fmt.Errorf("%s; err=%w", defaultError, scimError)
+		}
+	}
+
 	return nil
 }
 
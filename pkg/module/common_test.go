@@ -0,0 +1,115 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+func TestHandleCommonErrorsRedactsTokens(t *testing.T) {
+	const secret = "c2VjcmV0LXRva2Vu"
+	bodies := map[string]string{
+		"raw body":     `invalid header Authorization: Bearer ` + secret,
+		"verify error": `{"messageId":"CSIAH0001E","messageDescription":"invalid token Bearer ` + secret + `"}`,
+		"scim error":   `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"400","detail":"invalid token Bearer ` + secret + `"}`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			err := HandleCommonErrors(context.Background(), &xhttp.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       []byte(body),
+				Headers:    http.Header{},
+			}, "unable to get the resource")
+			if err == nil {
+				t.Fatal("HandleCommonErrors() = nil, want an error")
+			}
+
+			if strings.Contains(err.Error(), secret) {
+				t.Errorf("HandleCommonErrors() = %q, which contains the token", err.Error())
+			}
+		})
+	}
+}
+
+func TestHandleCommonErrorsTypedErrors(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       error
+	}{
+		{statusCode: http.StatusUnauthorized, want: ErrUnauthorized},
+		{statusCode: http.StatusForbidden, want: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := HandleCommonErrors(context.Background(), &xhttp.Response{
+				StatusCode: tt.statusCode,
+				Body:       []byte(`{"messageId":"CSIAH0001E"}`),
+				Headers:    http.Header{},
+			}, "unable to get the resource")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("HandleCommonErrors() = %v, want %v", err, tt.want)
+			}
+
+			if code := StatusCode(err); code != tt.statusCode {
+				t.Errorf("StatusCode() = %d, want %d", code, tt.statusCode)
+			}
+		})
+	}
+}
+
+func TestHandleCommonErrorsIgnoresOtherCodes(t *testing.T) {
+	err := HandleCommonErrors(context.Background(), &xhttp.Response{
+		StatusCode: http.StatusInternalServerError,
+		Headers:    http.Header{},
+	}, "unable to get the resource")
+	if err != nil {
+		t.Errorf("HandleCommonErrors() = %v, want nil", err)
+	}
+}
+
+func TestHandleCommonErrorsParsesSCIMErrors(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		body       string
+	}{
+		{statusCode: http.StatusConflict, body: `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"409","scimType":"uniqueness","detail":"exists"}`},
+		{statusCode: http.StatusPreconditionFailed, body: `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":412,"detail":"version mismatch"}`},
+		{statusCode: http.StatusInternalServerError, body: `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":500,"detail":"internal error"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := HandleCommonErrors(context.Background(), &xhttp.Response{
+				StatusCode: tt.statusCode,
+				Body:       []byte(tt.body),
+				Headers:    http.Header{},
+			}, "unable to get the resource")
+
+			var scimError *SCIMError
+			if !errors.As(err, &scimError) {
+				t.Fatalf("HandleCommonErrors() = %v, want a SCIMError", err)
+			}
+
+			if want := strconv.Itoa(tt.statusCode); scimError.Status != want {
+				t.Errorf("Status = %q, want %q", scimError.Status, want)
+			}
+
+			if code := StatusCode(err); code != tt.statusCode {
+				t.Errorf("StatusCode() = %d, want %d", code, tt.statusCode)
+			}
+		})
+	}
+}
+
+func TestParseSCIMErrorInvalidStatus(t *testing.T) {
+	if _, ok := ParseSCIMError([]byte(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":true,"detail":"bad"}`)); ok {
+		t.Error("ParseSCIMError() ok = true for a boolean status, want false")
+	}
+}
@@ -0,0 +1,109 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+)
+
+// ApplyGroupResult describes the changes made by ApplyGroup.
+type ApplyGroupResult struct {
+	// Created is set if the group did not exist and was created.
+	Created bool `json:"created" yaml:"created"`
+	// URI is the URL of the group.
+	URI string `json:"uri" yaml:"uri"`
+	// Operations are the operations applied to an existing group. It is empty if
+	// the group already matched.
+	Operations []GroupSCIMOpEntry `json:"operations,omitempty" yaml:"operations,omitempty"`
+}
+
+// ApplyGroup makes the group with the display name of desired match it, creating
+// the group if it does not exist. As with CreateGroup, member values are usernames,
+// or display names for members of type "Group", and owner values are usernames.
+// They are resolved to IDs before the groups are compared, so that only real
+// changes are applied. desired is not modified.
+func (c *GroupClient) ApplyGroup(ctx context.Context, auth *config.AuthConfig, desired *Group) (*ApplyGroupResult, error) {
+	vc := config.GetVerifyContext(ctx)
+	if desired == nil || len(desired.DisplayName) == 0 {
+		return nil, module.MakeSimpleError("the group display name is required")
+	}
+
+	group := *desired
+	group.Members = slices.Clone(desired.Members)
+	group.IBMGROUP.Owners = slices.Clone(desired.IBMGROUP.Owners)
+
+	if err := c.resolveOwners(ctx, auth, group.IBMGROUP.Owners); err != nil {
+		return nil, err
+	}
+
+	current, uri, err := c.GetGroup(ctx, auth, group.DisplayName)
+	if errors.Is(err, ErrGroupNotFound) {
+		uri, err := c.CreateGroup(ctx, auth, &group)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ApplyGroupResult{
+			Created: true,
+			URI:     uri,
+		}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.resolveMembers(ctx, auth, group.Members, nil); err != nil {
+		return nil, err
+	}
+
+	operations, err := DiffGroups(current, &group)
+	if err != nil {
+		vc.Logger.Errorf("unable to compare the group %s; err=%s", group.DisplayName, err.Error())
+		return nil, err
+	}
+
+	result := &ApplyGroupResult{
+		URI:        uri,
+		Operations: operations,
+	}
+
+	if len(operations) == 0 {
+		return result, nil
+	}
+
+	if err := c.patchGroup(ctx, auth, current.Id, operations); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// resolveOwners replaces the owner usernames with user IDs.
+func (c *GroupClient) resolveOwners(ctx context.Context, auth *config.AuthConfig, owners []Owner) error {
+	vc := config.GetVerifyContext(ctx)
+	if len(owners) == 0 {
+		return nil
+	}
+
+	usernames := make([]string, 0, len(owners))
+	for _, o := range owners {
+		usernames = append(usernames, o.Value)
+	}
+
+	userIDs, err := c.users.BatchResolveUserIDs(ctx, auth, usernames)
+	if err != nil {
+		vc.Logger.Errorf("unable to get user IDs for the group owners; err=%s", err.Error())
+		return fmt.Errorf("unable to get user IDs for the group owners; err=%w", err)
+	}
+
+	for i := range owners {
+		owners[i].Value = userIDs[owners[i].Value]
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+const (
+	apiBulk = "Bulk"
+
+	bulkRequestSchema  = "urn:ietf:params:scim:api:messages:2.0:BulkRequest"
+	patchRequestSchema = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+)
+
+type BulkClient struct {
+	endpoint
+
+	client xhttp.Clientx
+}
+
+type BulkRequest struct {
+	Schemas      []string        `json:"schemas" yaml:"schemas"`
+	FailOnErrors int             `json:"failOnErrors,omitempty" yaml:"failOnErrors,omitempty"`
+	Operations   []BulkOperation `json:"Operations" yaml:"Operations"`
+}
+
+type BulkOperation struct {
+	Method  string      `json:"method" yaml:"method"`
+	BulkId  string      `json:"bulkId,omitempty" yaml:"bulkId,omitempty"`
+	Version string      `json:"version,omitempty" yaml:"version,omitempty"`
+	Path    string      `json:"path" yaml:"path"`
+	Data    interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+type BulkResponse struct {
+	Schemas    []string              `json:"schemas" yaml:"schemas"`
+	Operations []BulkOperationResult `json:"Operations" yaml:"Operations"`
+}
+
+type BulkOperationResult struct {
+	Method   string          `json:"method" yaml:"method"`
+	BulkId   string          `json:"bulkId,omitempty" yaml:"bulkId,omitempty"`
+	Version  string          `json:"version,omitempty" yaml:"version,omitempty"`
+	Location string          `json:"location,omitempty" yaml:"location,omitempty"`
+	Status   json.Number     `json:"status" yaml:"status"`
+	Response json.RawMessage `json:"response,omitempty" yaml:"response,omitempty"`
+}
+
+// StatusCode returns the HTTP status of the operation, or 0 if it cannot be parsed.
+func (r *BulkOperationResult) StatusCode() int {
+	code, _ := strconv.Atoi(r.Status.String())
+	return code
+}
+
+// Failed returns the operations that did not succeed.
+func (r *BulkResponse) Failed() []BulkOperationResult {
+	failed := []BulkOperationResult{}
+	for _, op := range r.Operations {
+		if code := op.StatusCode(); code < 200 || code >= 300 {
+			failed = append(failed, op)
+		}
+	}
+
+	return failed
+}
+
+func NewBulkClient() *BulkClient {
+	return NewBulkClientWithClient(LimitConcurrency(xhttp.NewDefaultClient(), DefaultConcurrencyLimiter))
+}
+
+// NewBulkClientWithClient returns a BulkClient that makes requests using the provided client.
+func NewBulkClientWithClient(client xhttp.Clientx) *BulkClient {
+	return &BulkClient{
+		client: client,
+	}
+}
+
+// NewBulkCreateGroup returns an operation that creates the group. Member values
+// must already be user IDs, as usernames are not resolved in bulk requests.
+func NewBulkCreateGroup(bulkID string, group *Group) BulkOperation {
+	return BulkOperation{
+		Method: http.MethodPost,
+		BulkId: bulkID,
+		Path:   "/Groups",
+		Data:   group,
+	}
+}
+
+// NewBulkPatchGroup returns an operation that applies the SCIM operations to the group.
+func NewBulkPatchGroup(groupID string, operations []GroupSCIMOpEntry) BulkOperation {
+	return BulkOperation{
+		Method: http.MethodPatch,
+		Path:   "/Groups/" + groupID,
+		Data: GroupSCIMPatchRequest{
+			Schemas:    []string{patchRequestSchema},
+			Operations: operations,
+		},
+	}
+}
+
+// NewBulkDeleteGroup returns an operation that deletes the group.
+func NewBulkDeleteGroup(groupID string) BulkOperation {
+	return BulkOperation{
+		Method: http.MethodDelete,
+		Path:   "/Groups/" + groupID,
+	}
+}
+
+// BulkApply sends the operations in a single SCIM bulk request and returns the
+// result of each operation. failOnErrors is the number of failures after which the
+// server stops processing; zero leaves it to the server. If any operation fails,
+// the response is returned together with an error describing the failures.
+func (c *BulkClient) BulkApply(ctx context.Context, auth *config.AuthConfig, operations []BulkOperation, failOnErrors int) (*BulkResponse, error) {
+	vc := config.GetVerifyContext(ctx)
+	if len(operations) == 0 {
+		return nil, module.MakeSimpleError("at least one bulk operation is required")
+	}
+
+	u, _ := url.Parse(c.resourceURL(auth, apiBulk))
+	headers := scimBodyHeaders(auth)
+
+	b, err := json.Marshal(&BulkRequest{
+		Schemas:      []string{bulkRequestSchema},
+		FailOnErrors: failOnErrors,
+		Operations:   operations,
+	})
+	if err != nil {
+		vc.Logger.Errorf("unable to marshal the bulk request; err=%v", err)
+		return nil, fmt.Errorf("unable to marshal the bulk request; err=%v", err)
+	}
+
+	response, err := c.client.Post(ctx, u, headers, b)
+	if err != nil {
+		vc.Logger.Errorf("unable to apply the bulk request; err=%v", err)
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if err := module.HandleCommonErrors(ctx, response, "unable to apply the bulk request"); err != nil {
+			vc.Logger.Errorf("unable to apply the bulk request; err=%s", err.Error())
+			return nil, err
+		}
+
+		vc.Logger.Errorf("unable to apply the bulk request; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return nil, module.NewAPIError(response, fmt.Errorf("unable to apply the bulk request; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body))))
+	}
+
+	bulkResponse := &BulkResponse{}
+	if err := json.Unmarshal(response.Body, bulkResponse); err != nil {
+		vc.Logger.Errorf("unable to parse the bulk response; err=%s, body=%s", err, string(response.Body))
+		return nil, fmt.Errorf("unable to parse the bulk response")
+	}
+
+	if failed := bulkResponse.Failed(); len(failed) > 0 {
+		vc.Logger.Errorf("bulk request partially failed; failed=%d, total=%d", len(failed), len(operations))
+		return bulkResponse, fmt.Errorf("%d of %d bulk operations failed", len(failed), len(operations))
+	}
+
+	return bulkResponse, nil
+}
@@ -0,0 +1,72 @@
+package directory
+
+import (
+	"sync"
+)
+
+const (
+	defaultUserIDCacheSize = 1000
+)
+
+// userIDCache is a bounded, concurrency-safe map of usernames to user IDs. Entries
+// are keyed by tenant so that a client used against several tenants never returns
+// an ID from the wrong one. When the cache is full, the oldest entry is evicted.
+type userIDCache struct {
+	mu      sync.Mutex
+	maxSize int
+	entries map[string]string
+	order   []string
+}
+
+func newUserIDCache(maxSize int) *userIDCache {
+	return &userIDCache{
+		maxSize: maxSize,
+		entries: map[string]string{},
+	}
+}
+
+func (c *userIDCache) get(tenant string, username string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, ok := c.entries[cacheKey(tenant, username)]
+	return id, ok
+}
+
+func (c *userIDCache) put(tenant string, username string, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cacheKey(tenant, username)
+	if _, ok := c.entries[key]; !ok {
+		if len(c.order) >= c.maxSize {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, key)
+	}
+
+	c.entries[key] = id
+}
+
+func (c *userIDCache) remove(tenant string, username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cacheKey(tenant, username)
+	if _, ok := c.entries[key]; !ok {
+		return
+	}
+
+	delete(c.entries, key)
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func cacheKey(tenant string, username string) string {
+	return tenant + "\x00" + username
+}
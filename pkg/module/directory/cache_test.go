@@ -0,0 +1,105 @@
+package directory
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+)
+
+const jdoeResponse = `{"totalResults":1,"Resources":[{"id":"u1","userName":"jdoe"}]}`
+
+func TestUserIDCacheAvoidsSecondRequest(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, jdoeResponse)
+	client := NewUserClientWithClient(fake)
+	ctx := testContext(t)
+
+	for i := 0; i < 2; i++ {
+		ids, err := client.BatchResolveUserIDs(ctx, testAuth, []string{"jdoe"})
+		if err != nil {
+			t.Fatalf("BatchResolveUserIDs() err = %v, want nil", err)
+		}
+
+		if ids["jdoe"] != "u1" {
+			t.Errorf("BatchResolveUserIDs() = %v, want jdoe resolved to u1", ids)
+		}
+	}
+
+	if _, err := client.getUserId(ctx, testAuth, "jdoe"); err != nil {
+		t.Fatalf("getUserId() err = %v, want nil", err)
+	}
+
+	if n := len(fake.Requests()); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+}
+
+func TestUserIDCacheDisabled(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, jdoeResponse)
+	client := NewUserClientWithClient(fake)
+	client.SetCacheEnabled(false)
+	ctx := testContext(t)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.getUserId(ctx, testAuth, "jdoe"); err != nil {
+			t.Fatalf("getUserId() err = %v, want nil", err)
+		}
+	}
+
+	if n := len(fake.Requests()); n != 2 {
+		t.Errorf("sent %d requests, want 2", n)
+	}
+}
+
+func TestSetCacheEnabledWhileResolving(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, jdoeResponse)
+	client := NewUserClientWithClient(fake)
+	ctx := testContext(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := client.BatchResolveUserIDs(ctx, testAuth, []string{"jdoe"}); err != nil {
+				t.Errorf("BatchResolveUserIDs() err = %v, want nil", err)
+			}
+		}()
+		go func(enabled bool) {
+			defer wg.Done()
+			client.SetCacheEnabled(enabled)
+		}(i%2 == 0)
+	}
+
+	wg.Wait()
+}
+
+func TestUserIDCacheIsPerTenant(t *testing.T) {
+	cache := newUserIDCache(10)
+	cache.put("a.example.com", "jdoe", "u1")
+
+	if _, ok := cache.get("b.example.com", "jdoe"); ok {
+		t.Error("get() found the ID cached for another tenant")
+	}
+}
+
+func TestUserIDCacheEvictsOldest(t *testing.T) {
+	cache := newUserIDCache(2)
+	cache.put("t", "a", "1")
+	cache.put("t", "b", "2")
+	cache.put("t", "c", "3")
+
+	if _, ok := cache.get("t", "a"); ok {
+		t.Error("get(a) found an entry that should have been evicted")
+	}
+
+	for _, name := range []string{"b", "c"} {
+		if _, ok := cache.get("t", name); !ok {
+			t.Errorf("get(%s) did not find the entry", name)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package directory
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+const (
+	// DefaultMaxConcurrentRequests is the number of requests to a tenant that the
+	// directory clients may have in flight at the same time by default.
+	DefaultMaxConcurrentRequests = 10
+)
+
+var (
+	// DefaultConcurrencyLimiter is shared by the clients returned by NewGroupClient,
+	// NewUserClient and NewBulkClient.
+	DefaultConcurrencyLimiter = NewConcurrencyLimiter(DefaultMaxConcurrentRequests)
+)
+
+// ConcurrencyLimiter caps the number of requests in flight to each tenant. Unlike
+// rate limiting, it bounds how many requests are open at once rather than how often
+// they are sent.
+type ConcurrencyLimiter struct {
+	max  int
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+// NewConcurrencyLimiter returns a limiter that allows up to max requests in flight
+// to each tenant. A max below 1 is treated as 1.
+func NewConcurrencyLimiter(max int) *ConcurrencyLimiter {
+	if max < 1 {
+		max = 1
+	}
+
+	return &ConcurrencyLimiter{
+		max:  max,
+		sems: map[string]chan struct{}{},
+	}
+}
+
+// LimitConcurrency returns a client that sends requests through client while
+// holding a slot from the limiter for the tenant, which is taken from the host
+// of the request URL. Limiters can be shared by several clients.
+func LimitConcurrency(client xhttp.Clientx, limiter *ConcurrencyLimiter) xhttp.Clientx {
+	return &limitedClient{
+		client:  client,
+		limiter: limiter,
+	}
+}
+
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, tenant string) (func(), error) {
+	l.mu.Lock()
+	sem, ok := l.sems[tenant]
+	if !ok {
+		sem = make(chan struct{}, l.max)
+		l.sems[tenant] = sem
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	}
+}
+
+type limitedClient struct {
+	client  xhttp.Clientx
+	limiter *ConcurrencyLimiter
+}
+
+func (c *limitedClient) Get(ctx context.Context, url *url.URL, headers http.Header) (*xhttp.Response, error) {
+	release, err := c.limiter.acquire(ctx, url.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.Get(ctx, url, headers)
+}
+
+func (c *limitedClient) Post(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*xhttp.Response, error) {
+	release, err := c.limiter.acquire(ctx, url.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.Post(ctx, url, headers, body)
+}
+
+func (c *limitedClient) PostMultipart(ctx context.Context, url *url.URL, headers http.Header, files map[string][]byte, fields map[string]string) (*xhttp.Response, error) {
+	release, err := c.limiter.acquire(ctx, url.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.PostMultipart(ctx, url, headers, files, fields)
+}
+
+func (c *limitedClient) Put(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*xhttp.Response, error) {
+	release, err := c.limiter.acquire(ctx, url.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.Put(ctx, url, headers, body)
+}
+
+func (c *limitedClient) PutMultipart(ctx context.Context, url *url.URL, headers http.Header, files map[string][]byte, fields map[string]string) (*xhttp.Response, error) {
+	release, err := c.limiter.acquire(ctx, url.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.PutMultipart(ctx, url, headers, files, fields)
+}
+
+func (c *limitedClient) Patch(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*xhttp.Response, error) {
+	release, err := c.limiter.acquire(ctx, url.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.Patch(ctx, url, headers, body)
+}
+
+func (c *limitedClient) Delete(ctx context.Context, url *url.URL, headers http.Header) (*xhttp.Response, error) {
+	release, err := c.limiter.acquire(ctx, url.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.Delete(ctx, url, headers)
+}
+
+func (c *limitedClient) Head(ctx context.Context, url *url.URL, headers http.Header) (*xhttp.Response, error) {
+	release, err := c.limiter.acquire(ctx, url.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.Head(ctx, url, headers)
+}
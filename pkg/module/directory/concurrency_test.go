@@ -0,0 +1,96 @@
+package directory
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+// trackingClient holds each GET open briefly and records the highest number of
+// requests in flight at once.
+type trackingClient struct {
+	*directorytest.FakeClient
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (c *trackingClient) Get(ctx context.Context, u *url.URL, headers http.Header) (*xhttp.Response, error) {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+
+	for {
+		max := c.maxInFlight.Load()
+		if n <= max || c.maxInFlight.CompareAndSwap(max, n) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	return c.FakeClient.Get(ctx, u, headers)
+}
+
+func TestLimitConcurrency(t *testing.T) {
+	const limit = 3
+	tracking := &trackingClient{
+		FakeClient: directorytest.NewFakeClient().On(http.MethodGet, "/v2.0/Users", http.StatusOK, "{}"),
+	}
+	client := LimitConcurrency(tracking, NewConcurrencyLimiter(limit))
+	u, _ := url.Parse("https://tenant.example.com/v2.0/Users")
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Get(context.Background(), u, nil); err != nil {
+				t.Errorf("Get() err = %v, want nil", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max := tracking.maxInFlight.Load(); max > limit {
+		t.Errorf("%d requests were in flight at once, want at most %d", max, limit)
+	}
+}
+
+func TestLimitConcurrencyIsPerTenant(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1)
+	release, err := limiter.acquire(context.Background(), "a.example.com")
+	if err != nil {
+		t.Fatalf("acquire() err = %v, want nil", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	releaseOther, err := limiter.acquire(ctx, "b.example.com")
+	if err != nil {
+		t.Fatalf("acquire() for another tenant err = %v, want nil", err)
+	}
+	releaseOther()
+}
+
+func TestLimitConcurrencyHonoursCancellation(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1)
+	release, err := limiter.acquire(context.Background(), "a.example.com")
+	if err != nil {
+		t.Fatalf("acquire() err = %v, want nil", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := limiter.acquire(ctx, "a.example.com"); err == nil {
+		t.Error("acquire() err = nil, want the context error while the slot is held")
+	}
+}
@@ -0,0 +1,159 @@
+package directory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+)
+
+// CopyGroupResult describes the outcome of CopyGroup.
+type CopyGroupResult struct {
+	// URI is the URL of the group in the destination tenant.
+	URI string
+	// Created is false if the group already existed in the destination tenant and
+	// was updated to match the source.
+	Created bool
+	// UnmappedMembers lists the members of the source group, by username or
+	// display name, that do not exist in the destination tenant and were left out.
+	UnmappedMembers []string
+	// UnmappedOwners lists the owners of the source group that do not exist in the
+	// destination tenant and were left out.
+	UnmappedOwners []string
+}
+
+// CopyGroup reads the group from the source tenant and recreates it in the
+// destination tenant, or updates it there if it already exists. Members and owners
+// are matched by username, and nested groups by display name. Those that cannot be
+// matched in the destination are left out and reported. Schema extensions that the
+// destination tenant may not know about are not copied, so that the copy does not
+// fail on differences between the tenants.
+func (c *GroupClient) CopyGroup(ctx context.Context, srcAuth *config.AuthConfig, dstAuth *config.AuthConfig, groupName string) (*CopyGroupResult, error) {
+	vc := config.GetVerifyContext(ctx)
+	source, _, err := c.GetGroup(ctx, srcAuth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the Group from the source tenant; err=%s", err.Error())
+		return nil, err
+	}
+
+	userIDs := []string{}
+	for _, m := range source.Members {
+		if !isGroupMember(m.Type) {
+			userIDs = append(userIDs, m.Value)
+		}
+	}
+
+	for _, owner := range source.IBMGROUP.Owners {
+		userIDs = append(userIDs, owner.Value)
+	}
+
+	usernames, err := c.usernamesByID(ctx, srcAuth, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupNames := map[string]string{}
+	for _, m := range source.Members {
+		if !isGroupMember(m.Type) {
+			continue
+		}
+
+		nested, _, err := c.GetGroupByID(ctx, srcAuth, m.Value, WithAttributes("displayName"))
+		if err != nil {
+			vc.Logger.Warnf("unable to resolve the nested group %s; err=%s", m.Value, err.Error())
+			continue
+		}
+		groupNames[m.Value] = nested.DisplayName
+	}
+
+	group := portableGroup(ctx, source, usernames, groupNames)
+	group.Extensions = nil
+	group.AdditionalProperties = nil
+	group.Schemas = slices.DeleteFunc(slices.Clone(source.Schemas), func(schema string) bool {
+		return schema != coreGroupSchema && schema != ibmGroupExtensionSchema && schema != ibmNotificationExtensionSchema
+	})
+	if len(group.Schemas) == 0 {
+		group.Schemas = defaultGroupSchemas(group)
+	}
+
+	result := &CopyGroupResult{
+		UnmappedMembers: []string{},
+		UnmappedOwners:  []string{},
+	}
+
+	// leave out what the destination tenant does not have
+	dstUserIDs, err := c.users.BatchResolveUserIDs(ctx, dstAuth, groupUsernames(group))
+	if err != nil && dstUserIDs == nil {
+		vc.Logger.Errorf("unable to get the user IDs in the destination tenant; err=%s", err.Error())
+		return nil, err
+	}
+
+	members := []Member{}
+	for _, m := range group.Members {
+		if isGroupMember(m.Type) {
+			if _, err := c.getGroupId(ctx, dstAuth, m.Value); err != nil {
+				vc.Logger.Warnf("the nested group %s is not in the destination tenant; err=%s", m.Value, err.Error())
+				result.UnmappedMembers = append(result.UnmappedMembers, m.Value)
+				continue
+			}
+		} else if _, ok := dstUserIDs[m.Value]; !ok {
+			result.UnmappedMembers = append(result.UnmappedMembers, m.Value)
+			continue
+		}
+
+		members = append(members, m)
+	}
+	group.Members = members
+
+	owners := []Owner{}
+	for _, owner := range group.IBMGROUP.Owners {
+		if _, ok := dstUserIDs[owner.Value]; !ok {
+			result.UnmappedOwners = append(result.UnmappedOwners, owner.Value)
+			continue
+		}
+
+		owners = append(owners, owner)
+	}
+	group.IBMGROUP.Owners = owners
+
+	// members and owners of the source that could not be resolved there
+	result.UnmappedMembers = append(result.UnmappedMembers, unresolved(source.Members, usernames, groupNames)...)
+	for _, owner := range source.IBMGROUP.Owners {
+		if _, ok := usernames[owner.Value]; !ok {
+			result.UnmappedOwners = append(result.UnmappedOwners, owner.Value)
+		}
+	}
+
+	if len(result.UnmappedMembers) > 0 || len(result.UnmappedOwners) > 0 {
+		vc.Logger.Warnf("the members %s and owners %s of the group %s could not be mapped to the destination tenant",
+			strings.Join(result.UnmappedMembers, ", "), strings.Join(result.UnmappedOwners, ", "), groupName)
+	}
+
+	applied, err := c.ApplyGroup(ctx, dstAuth, group)
+	if err != nil {
+		vc.Logger.Errorf("unable to copy the Group to the destination tenant; err=%s", err.Error())
+		return nil, err
+	}
+
+	result.URI = applied.URI
+	result.Created = applied.Created
+	return result, nil
+}
+
+// unresolved returns the values of the members that are in neither map.
+func unresolved(members []Member, usernames map[string]string, groupNames map[string]string) []string {
+	values := []string{}
+	for _, m := range members {
+		names := usernames
+		if isGroupMember(m.Type) {
+			names = groupNames
+		}
+
+		if _, ok := names[m.Value]; !ok {
+			values = append(values, m.Value)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,167 @@
+package directory
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+)
+
+// DiffGroups returns the SCIM PATCH operations that turn current into desired.
+// Members and owners are matched by value, so they must hold resource IDs in both
+// groups, and their order does not matter. An empty description or external ID in
+// desired removes it from the group. The visibility and notification settings are
+// only changed if VisibleSet and NotificationSet are set on desired, so that a
+// document that leaves them out does not reset them. No operations are returned if
+// the groups already match.
+func DiffGroups(current *Group, desired *Group) ([]GroupSCIMOpEntry, error) {
+	if current == nil || desired == nil {
+		return nil, module.MakeSimpleError("both the current and the desired group are required")
+	}
+
+	if len(current.Id) > 0 && len(desired.Id) > 0 && current.Id != desired.Id {
+		return nil, module.MakeSimpleError(fmt.Sprintf("the groups have different IDs; current=%s, desired=%s", current.Id, desired.Id))
+	}
+
+	// normalizing both sides keeps the order of the operations stable
+	current = NormalizeGroup(current)
+	desired = NormalizeGroup(desired)
+
+	builder := NewPatchBuilder()
+	if len(desired.DisplayName) > 0 && desired.DisplayName != current.DisplayName {
+		builder.Replace("displayName", desired.DisplayName)
+	}
+
+	diffString(builder, "externalId", current.ExternalId, desired.ExternalId)
+
+	if desired.VisibleSet && desired.Visible != current.Visible {
+		builder.Replace("visible", desired.Visible)
+	}
+
+	diffString(builder, ibmGroupExtensionSchema+":description", current.IBMGROUP.Description, desired.IBMGROUP.Description)
+	diffMembers(builder, current.Members, desired.Members)
+	diffOwners(builder, current.IBMGROUP.Owners, desired.IBMGROUP.Owners)
+
+	if desired.NotificationSet && desired.Notification != current.Notification {
+		builder.Replace(ibmNotificationExtensionSchema, desired.Notification)
+	}
+
+	if len(builder.operations) == 0 && builder.err == nil {
+		return []GroupSCIMOpEntry{}, nil
+	}
+
+	return builder.Operations()
+}
+
+// NormalizeGroup returns a copy of the group in a canonical form, with the members,
+// owners and schemas sorted, so that groups holding the same data compare equal.
+// Members are sorted by value and then by type.
+func NormalizeGroup(group *Group) *Group {
+	if group == nil {
+		return nil
+	}
+
+	g := *group
+	g.Schemas = slices.Clone(group.Schemas)
+	slices.Sort(g.Schemas)
+
+	g.Members = slices.Clone(group.Members)
+	slices.SortStableFunc(g.Members, func(a, b Member) int {
+		if c := cmp.Compare(a.Value, b.Value); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Type, b.Type)
+	})
+
+	g.IBMGROUP.Owners = slices.Clone(group.IBMGROUP.Owners)
+	slices.SortStableFunc(g.IBMGROUP.Owners, func(a, b Owner) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+
+	return &g
+}
+
+func diffString(builder *PatchBuilder, path string, current string, desired string) {
+	if current == desired {
+		return
+	}
+
+	if len(desired) == 0 {
+		builder.Remove(path)
+	} else {
+		builder.Replace(path, desired)
+	}
+}
+
+func diffMembers(builder *PatchBuilder, current []Member, desired []Member) {
+	existing := map[string]bool{}
+	for _, m := range current {
+		existing[m.Value] = true
+	}
+
+	wanted := map[string]bool{}
+	added := []map[string]interface{}{}
+	for _, m := range desired {
+		if wanted[m.Value] {
+			continue
+		}
+		wanted[m.Value] = true
+
+		if existing[m.Value] {
+			continue
+		}
+
+		member := map[string]interface{}{
+			"value": m.Value,
+		}
+		if len(m.Type) > 0 {
+			member["type"] = m.Type
+		}
+		added = append(added, member)
+	}
+
+	if len(added) > 0 {
+		builder.Add("members", added)
+	}
+
+	for _, m := range current {
+		if !wanted[m.Value] {
+			builder.Remove(fmt.Sprintf("members[value eq %s]", quoteFilterValue(m.Value)))
+		}
+	}
+}
+
+func diffOwners(builder *PatchBuilder, current []Owner, desired []Owner) {
+	existing := map[string]bool{}
+	for _, o := range current {
+		existing[o.Value] = true
+	}
+
+	wanted := map[string]bool{}
+	added := []map[string]interface{}{}
+	for _, o := range desired {
+		if wanted[o.Value] {
+			continue
+		}
+		wanted[o.Value] = true
+
+		if !existing[o.Value] {
+			added = append(added, map[string]interface{}{
+				"value": o.Value,
+			})
+		}
+	}
+
+	if len(added) > 0 {
+		operation := addOwnersOperation(len(current) > 0, added)
+		builder.Add(operation.Path, operation.Value)
+	}
+
+	for _, o := range current {
+		if !wanted[o.Value] {
+			builder.Remove(fmt.Sprintf("%s:owners[value eq %s]", ibmGroupExtensionSchema, quoteFilterValue(o.Value)))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package directory
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeGroupIgnoresOrder(t *testing.T) {
+	a := &Group{
+		Schemas:     []string{ibmGroupExtensionSchema, coreGroupSchema},
+		DisplayName: "Sales",
+		Members: []Member{
+			{Type: "User", Value: "u2"},
+			{Type: "Group", Value: "g1"},
+			{Type: "User", Value: "u1"},
+		},
+		IBMGROUP: IBMGROUPExtension{
+			Owners: []Owner{{Value: "o2"}, {Value: "o1"}},
+		},
+	}
+	b := &Group{
+		Schemas:     []string{coreGroupSchema, ibmGroupExtensionSchema},
+		DisplayName: "Sales",
+		Members: []Member{
+			{Type: "User", Value: "u1"},
+			{Type: "User", Value: "u2"},
+			{Type: "Group", Value: "g1"},
+		},
+		IBMGROUP: IBMGROUPExtension{
+			Owners: []Owner{{Value: "o1"}, {Value: "o2"}},
+		},
+	}
+
+	if !reflect.DeepEqual(NormalizeGroup(a), NormalizeGroup(b)) {
+		t.Errorf("NormalizeGroup() differs:\n%+v\n%+v", NormalizeGroup(a), NormalizeGroup(b))
+	}
+
+	// the input is left as it was
+	if a.Members[0].Value != "u2" || a.Schemas[0] != ibmGroupExtensionSchema {
+		t.Error("NormalizeGroup() changed its input")
+	}
+}
+
+func TestDiffGroupsIgnoresMemberOrder(t *testing.T) {
+	current := &Group{
+		DisplayName: "Sales",
+		Members:     []Member{{Value: "u2"}, {Value: "u1"}},
+	}
+	desired := &Group{
+		DisplayName: "Sales",
+		Members:     []Member{{Value: "u1"}, {Value: "u2"}},
+	}
+
+	operations, err := DiffGroups(current, desired)
+	if err != nil {
+		t.Fatalf("DiffGroups() err = %v, want nil", err)
+	}
+
+	if len(operations) != 0 {
+		t.Errorf("DiffGroups() = %+v, want no operations", operations)
+	}
+}
+
+func TestDiffGroupsOnlyAppliesSettingsThatAreSet(t *testing.T) {
+	current := &Group{
+		DisplayName:  "Sales",
+		Visible:      true,
+		Notification: GroupNotification{NotifyType: "EMAIL", NotifyManager: true},
+	}
+
+	tests := []struct {
+		name    string
+		desired string
+		want    []string
+	}{
+		{
+			name:    "left out",
+			desired: `{"displayName":"Sales"}`,
+		},
+		{
+			name:    "visible set to false",
+			desired: `{"displayName":"Sales","visible":false}`,
+			want:    []string{"visible"},
+		},
+		{
+			name:    "notification cleared",
+			desired: `{"displayName":"Sales","urn:ietf:params:scim:schemas:extension:ibm:2.0:Notification":{"notifyType":"NONE"}}`,
+			want:    []string{ibmNotificationExtensionSchema},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := &Group{}
+			if err := json.Unmarshal([]byte(tt.desired), desired); err != nil {
+				t.Fatalf("unable to decode the desired group; err=%v", err)
+			}
+
+			operations, err := DiffGroups(current, desired)
+			if err != nil {
+				t.Fatalf("DiffGroups() err = %v, want nil", err)
+			}
+
+			paths := []string{}
+			for _, op := range operations {
+				paths = append(paths, op.Path)
+			}
+			if len(paths) != len(tt.want) || (len(paths) > 0 && !reflect.DeepEqual(paths, tt.want)) {
+				t.Errorf("DiffGroups() changed %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Package directorytest provides a fake Clientx for testing code that uses the
+// directory clients without a live tenant.
+//
+//	fake := directorytest.NewFakeClient().
+//		On(http.MethodGet, "/v2.0/Users", http.StatusOK, `{"totalResults":1,"Resources":[{"id":"u1","userName":"jdoe"}]}`).
+//		On(http.MethodPost, "/v2.0/Groups", http.StatusCreated, `{"id":"g1"}`)
+//
+//	client := directory.NewGroupClientWithClient(fake)
+//	_, err := client.CreateGroup(ctx, auth, group)
+//
+//	// fake.Requests() holds the lookup of jdoe followed by the POST, with the
+//	// member value replaced by u1 in its body
+package directorytest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+// Request is a request received by the FakeClient.
+type Request struct {
+	Method  string
+	URL     *url.URL
+	Headers http.Header
+	Body    []byte
+
+	// Files and Fields hold the parts of a multipart request.
+	Files  map[string][]byte
+	Fields map[string]string
+}
+
+// FakeClient implements xhttp.Clientx by returning canned responses keyed by the
+// method and path of the request, and records every request it receives. It is
+// safe for concurrent use.
+type FakeClient struct {
+	mu        sync.Mutex
+	responses map[string][]*xhttp.Response
+	requests  []Request
+}
+
+var _ xhttp.Clientx = &FakeClient{}
+
+// NewFakeClient returns a FakeClient without any responses.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		responses: map[string][]*xhttp.Response{},
+	}
+}
+
+// On registers the response to a request with the method and path, ignoring the
+// query. Responses registered for the same method and path are returned in order,
+// and the last one is repeated once the others are used up.
+func (f *FakeClient) On(method string, path string, statusCode int, body string) *FakeClient {
+	return f.OnResponse(method, path, &xhttp.Response{
+		StatusCode: statusCode,
+		Body:       []byte(body),
+		Headers:    http.Header{},
+	})
+}
+
+// OnResponse registers a full response, including headers, as On does.
+func (f *FakeClient) OnResponse(method string, path string, response *xhttp.Response) *FakeClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := responseKey(method, path)
+	f.responses[key] = append(f.responses[key], response)
+	return f
+}
+
+// Requests returns the requests received so far, in order.
+func (f *FakeClient) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Request{}, f.requests...)
+}
+
+// Reset forgets the registered responses and the received requests.
+func (f *FakeClient) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.responses = map[string][]*xhttp.Response{}
+	f.requests = nil
+}
+
+func (f *FakeClient) Get(ctx context.Context, url *url.URL, headers http.Header) (*xhttp.Response, error) {
+	return f.handle(Request{Method: http.MethodGet, URL: url, Headers: headers})
+}
+
+func (f *FakeClient) Post(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*xhttp.Response, error) {
+	return f.handle(Request{Method: http.MethodPost, URL: url, Headers: headers, Body: body})
+}
+
+func (f *FakeClient) PostMultipart(ctx context.Context, url *url.URL, headers http.Header, files map[string][]byte, fields map[string]string) (*xhttp.Response, error) {
+	return f.handle(Request{Method: http.MethodPost, URL: url, Headers: headers, Files: files, Fields: fields})
+}
+
+func (f *FakeClient) Put(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*xhttp.Response, error) {
+	return f.handle(Request{Method: http.MethodPut, URL: url, Headers: headers, Body: body})
+}
+
+func (f *FakeClient) PutMultipart(ctx context.Context, url *url.URL, headers http.Header, files map[string][]byte, fields map[string]string) (*xhttp.Response, error) {
+	return f.handle(Request{Method: http.MethodPut, URL: url, Headers: headers, Files: files, Fields: fields})
+}
+
+func (f *FakeClient) Patch(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*xhttp.Response, error) {
+	return f.handle(Request{Method: http.MethodPatch, URL: url, Headers: headers, Body: body})
+}
+
+func (f *FakeClient) Delete(ctx context.Context, url *url.URL, headers http.Header) (*xhttp.Response, error) {
+	return f.handle(Request{Method: http.MethodDelete, URL: url, Headers: headers})
+}
+
+func (f *FakeClient) Head(ctx context.Context, url *url.URL, headers http.Header) (*xhttp.Response, error) {
+	return f.handle(Request{Method: http.MethodHead, URL: url, Headers: headers})
+}
+
+func (f *FakeClient) handle(request Request) (*xhttp.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, request)
+
+	key := responseKey(request.Method, request.URL.Path)
+	responses := f.responses[key]
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("no response registered for %s", key)
+	}
+
+	response := responses[0]
+	if len(responses) > 1 {
+		f.responses[key] = responses[1:]
+	}
+
+	// return a copy so that callers cannot change the registered response
+	return &xhttp.Response{
+		StatusCode: response.StatusCode,
+		Body:       append([]byte{}, response.Body...),
+		Headers:    response.Headers.Clone(),
+	}, nil
+}
+
+func responseKey(method string, path string) string {
+	return method + " " + path
+}
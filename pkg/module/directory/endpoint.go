@@ -0,0 +1,49 @@
+package directory
+
+import (
+	"strings"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+)
+
+const (
+	defaultAPIVersion = "v2.0"
+)
+
+// endpoint builds the URLs of the SCIM resources. It is embedded in the directory
+// clients, which default to the tenant URL of the auth config followed by v2.0.
+type endpoint struct {
+	baseURL    string
+	apiVersion string
+}
+
+// SetBaseURL sends requests to the base URL, such as http://127.0.0.1:8080, instead
+// of the tenant URL of the auth config. An empty value restores the default.
+func (e *endpoint) SetBaseURL(baseURL string) {
+	e.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetAPIVersion sets the version of the SCIM API, which defaults to v2.0.
+func (e *endpoint) SetAPIVersion(version string) {
+	e.apiVersion = strings.Trim(version, "/")
+}
+
+// resourceURL returns the URL of the resource type, or of the resource with the ID
+// if one is provided.
+func (e *endpoint) resourceURL(auth *config.AuthConfig, resource string, id ...string) string {
+	version := e.apiVersion
+	if len(version) == 0 {
+		version = defaultAPIVersion
+	}
+
+	return e.apiURL(auth, append([]string{version, resource}, id...)...)
+}
+
+// apiURL returns the URL of a path outside the SCIM API, such as v1.0/events.
+func (e *endpoint) apiURL(auth *config.AuthConfig, path ...string) string {
+	if len(e.baseURL) == 0 {
+		return auth.TenantURL(path...)
+	}
+
+	return strings.Join(append([]string{e.baseURL}, path...), "/")
+}
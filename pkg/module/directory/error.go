@@ -0,0 +1,50 @@
+package directory
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrGroupNotFound is returned when no group matches the lookup.
+	ErrGroupNotFound = errors.New("group not found")
+
+	// ErrGroupExists is returned when a group cannot be created because one with
+	// the same name already exists.
+	ErrGroupExists = errors.New("group already exists")
+
+	// ErrAmbiguousGroup is returned when more than one group matches a lookup by name.
+	ErrAmbiguousGroup = errors.New("multiple groups found")
+
+	// ErrDuplicateGroup is returned for groups that share a display name with another
+	// group in the same request.
+	ErrDuplicateGroup = errors.New("more than one group has the display name")
+
+	// ErrUserNotFound is returned when no user matches the lookup.
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUserExists is returned when a user cannot be created because one with the
+	// same username already exists.
+	ErrUserExists = errors.New("user already exists")
+
+	// ErrUserConflict is returned when a user update clashes with another user, such
+	// as a username that is already taken.
+	ErrUserConflict = errors.New("user update conflicts with an existing user")
+
+	// ErrUnsupported is returned when the tenant does not provide the API a request needs.
+	ErrUnsupported = errors.New("not supported by the tenant")
+)
+
+// joinFailures combines the failures keyed by name into one error, or returns nil.
+func joinFailures(failed map[string]error) error {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	errs := []error{}
+	for name, err := range failed {
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+
+	return errors.Join(errs...)
+}
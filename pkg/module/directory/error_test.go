@@ -0,0 +1,107 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+var errTransport = errors.New("connection reset")
+
+// failingPatchClient fails every PATCH with errTransport.
+type failingPatchClient struct {
+	*directorytest.FakeClient
+}
+
+func (c *failingPatchClient) Patch(ctx context.Context, u *url.URL, headers http.Header, body []byte) (*xhttp.Response, error) {
+	return nil, errTransport
+}
+
+func TestGroupErrorsAreWrapped(t *testing.T) {
+	tests := []struct {
+		name   string
+		client func() xhttp.Clientx
+		call   func(ctx context.Context, client *GroupClient) error
+		want   error
+	}{
+		{
+			name: "unauthorized lookup",
+			client: func() xhttp.Clientx {
+				return directorytest.NewFakeClient().On(http.MethodGet, "/v2.0/Groups", http.StatusUnauthorized, "")
+			},
+			call: func(ctx context.Context, client *GroupClient) error {
+				return client.DeleteGroup(ctx, testAuth, "Sales")
+			},
+			want: module.ErrUnauthorized,
+		},
+		{
+			name: "group not found",
+			client: func() xhttp.Clientx {
+				return directorytest.NewFakeClient().On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":0}`)
+			},
+			call: func(ctx context.Context, client *GroupClient) error {
+				_, err := client.UpdateGroupWithResult(ctx, testAuth, "Sales", []GroupSCIMOpEntry{{Op: "remove", Path: "members"}})
+				return err
+			},
+			want: ErrGroupNotFound,
+		},
+		{
+			name: "forbidden delete",
+			client: func() xhttp.Clientx {
+				return onGroupLookup(directorytest.NewFakeClient(), "g1").
+					On(http.MethodDelete, "/v2.0/Groups/g1", http.StatusForbidden, "")
+			},
+			call: func(ctx context.Context, client *GroupClient) error {
+				return client.DeleteGroup(ctx, testAuth, "Sales")
+			},
+			want: module.ErrForbidden,
+		},
+		{
+			name: "transport error",
+			client: func() xhttp.Clientx {
+				return &failingPatchClient{onGroupLookup(directorytest.NewFakeClient(), "g1")}
+			},
+			call: func(ctx context.Context, client *GroupClient) error {
+				return client.ClearGroupMembers(ctx, testAuth, "Sales")
+			},
+			want: errTransport,
+		},
+		{
+			name: "rename onto an existing group",
+			client: func() xhttp.Clientx {
+				return onGroupLookup(directorytest.NewFakeClient(), "g1")
+			},
+			call: func(ctx context.Context, client *GroupClient) error {
+				return client.UpdateGroupDisplayName(ctx, testAuth, "Sales", "Marketing")
+			},
+			want: ErrGroupExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewGroupClientWithClient(tt.client())
+			err := tt.call(testContext(t), client)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want it to wrap %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupErrorsKeepStatusCode(t *testing.T) {
+	fake := onGroupLookup(directorytest.NewFakeClient(), "g1").
+		On(http.MethodDelete, "/v2.0/Groups/g1", http.StatusBadRequest, `{"messageId":"CSIBT0001E","messageDescription":"group has members"}`)
+	client := NewGroupClientWithClient(fake)
+
+	err := client.DeleteGroup(testContext(t), testAuth, "Sales")
+	if code := module.StatusCode(err); code != http.StatusBadRequest {
+		t.Errorf("StatusCode() = %d, want %d; err=%v", code, http.StatusBadRequest, err)
+	}
+}
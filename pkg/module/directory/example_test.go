@@ -0,0 +1,54 @@
+package directory_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory"
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+func ExampleNewGroupClientWithClient() {
+	ctx, _ := config.NewContextWithVerifyContext(context.Background(), logx.NewLoggerWithWriter("example", slog.LevelError, io.Discard))
+	auth := &config.AuthConfig{
+		Tenant: "tenant.example.com",
+		Token:  "token",
+	}
+
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, `{"totalResults":1,"Resources":[{"id":"u1","userName":"jdoe"}]}`).
+		On(http.MethodPost, "/v2.0/Groups", http.StatusCreated, `{"id":"g1"}`)
+
+	client := directory.NewGroupClientWithClient(fake)
+	uri, err := client.CreateGroup(ctx, auth, &directory.Group{
+		DisplayName: "Sales",
+		Members: []directory.Member{
+			{Type: "User", Value: "jdoe"},
+		},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	requests := fake.Requests()
+	fmt.Println(uri)
+	fmt.Println(requests[0].Method, requests[0].URL.Query().Get("filter"))
+
+	posted := &directory.Group{}
+	if err := json.Unmarshal(requests[1].Body, posted); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(requests[1].Method, posted.DisplayName, posted.Members[0].Value)
+	// Output:
+	// https://tenant.example.com/v2.0/Groups/g1
+	// GET userName eq "jdoe"
+	// POST Sales u1
+}
@@ -0,0 +1,176 @@
+package directory
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+)
+
+const (
+	groupExportKind    = "IBMVerifyList"
+	groupKind          = "IBMVerifyGroup"
+	groupExportVersion = "2.0"
+)
+
+// groupExport is the portable document written by ExportGroups and read by
+// ImportGroups. It has the layout of 'verifyctl get groups', so each item can also
+// be read on its own by ParseGroup.
+type groupExport struct {
+	Kind       string            `json:"kind" yaml:"kind"`
+	APIVersion string            `json:"apiVersion" yaml:"apiVersion"`
+	Items      []groupExportItem `json:"items" yaml:"items"`
+}
+
+type groupExportItem struct {
+	Kind       string `json:"kind" yaml:"kind"`
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Data       *Group `json:"data" yaml:"data"`
+}
+
+// ExportGroups writes every group to the writer as a JSON or YAML document that
+// ImportGroups can recreate in another tenant. Tenant specific values are left out:
+// user members and owners are written as usernames, nested groups by display name,
+// and IDs, references and metadata are dropped. Schemas and extensions are kept.
+// Members and owners that can no longer be resolved are left out with a warning.
+func (c *GroupClient) ExportGroups(ctx context.Context, auth *config.AuthConfig, writer io.Writer, format string) error {
+	vc := config.GetVerifyContext(ctx)
+	groups, err := c.getAllGroups(ctx, auth, "", "displayName")
+	if err != nil {
+		vc.Logger.Errorf("unable to get the Groups; err=%s", err.Error())
+		return err
+	}
+
+	groupNames := map[string]string{}
+	userIDs := []string{}
+	for _, group := range groups {
+		groupNames[group.Id] = group.DisplayName
+		for _, m := range group.Members {
+			if !isGroupMember(m.Type) {
+				userIDs = append(userIDs, m.Value)
+			}
+		}
+
+		for _, owner := range group.IBMGROUP.Owners {
+			userIDs = append(userIDs, owner.Value)
+		}
+	}
+
+	usernames, err := c.usernamesByID(ctx, auth, userIDs)
+	if err != nil {
+		return err
+	}
+
+	doc := &groupExport{
+		Kind:       groupExportKind,
+		APIVersion: groupExportVersion,
+		Items:      []groupExportItem{},
+	}
+	for i := range groups {
+		group := portableGroup(ctx, &groups[i], usernames, groupNames)
+		doc.Items = append(doc.Items, groupExportItem{
+			Kind:       groupKind,
+			APIVersion: groupExportVersion,
+			Data:       group,
+		})
+	}
+
+	b, err := formatResource(doc, format)
+	if err != nil {
+		vc.Logger.Errorf("unable to format the Groups; err=%s", err.Error())
+		return err
+	}
+
+	if _, err := writer.Write(b); err != nil {
+		vc.Logger.Errorf("unable to write the Groups; err=%s", err.Error())
+		return fmt.Errorf("unable to write the Groups; err=%w", err)
+	}
+
+	return nil
+}
+
+// portableGroup returns a copy of the group with the tenant specific values
+// replaced or removed.
+func portableGroup(ctx context.Context, group *Group, usernames map[string]string, groupNames map[string]string) *Group {
+	vc := config.GetVerifyContext(ctx)
+	portable := *group
+	portable.Id = ""
+	portable.Meta = GroupMeta{}
+	portable.MemberCount = 0
+
+	portable.Members = []Member{}
+	for _, m := range group.Members {
+		names := usernames
+		if isGroupMember(m.Type) {
+			names = groupNames
+		}
+
+		name, ok := names[m.Value]
+		if !ok {
+			vc.Logger.Warnf("unable to resolve the member %s of the group %s; it is left out of the export", m.Value, group.DisplayName)
+			continue
+		}
+
+		portable.Members = append(portable.Members, Member{
+			Type:  m.Type,
+			Value: name,
+		})
+	}
+
+	portable.IBMGROUP.Owners = []Owner{}
+	for _, owner := range group.IBMGROUP.Owners {
+		name, ok := usernames[owner.Value]
+		if !ok {
+			vc.Logger.Warnf("unable to resolve the owner %s of the group %s; it is left out of the export", owner.Value, group.DisplayName)
+			continue
+		}
+
+		portable.IBMGROUP.Owners = append(portable.IBMGROUP.Owners, Owner{
+			Value: name,
+		})
+	}
+
+	return &portable
+}
+
+// usernamesByID looks up the username of each user ID. IDs that cannot be
+// resolved are missing from the result.
+func (c *GroupClient) usernamesByID(ctx context.Context, auth *config.AuthConfig, ids []string) (map[string]string, error) {
+	vc := config.GetVerifyContext(ctx)
+	usernames := map[string]string{}
+	mu := sync.Mutex{}
+
+	sem := make(chan struct{}, memberResolveWorkers)
+	wg := sync.WaitGroup{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] || ctx.Err() != nil {
+			continue
+		}
+		seen[id] = true
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			user, err := c.users.getUserByID(ctx, auth, id)
+			if err != nil {
+				vc.Logger.Warnf("unable to resolve the user %s; err=%s", id, err.Error())
+				return
+			}
+
+			mu.Lock()
+			usernames[id] = user.UserName
+			mu.Unlock()
+		}(id)
+	}
+
+	wg.Wait()
+	return usernames, ctx.Err()
+}
@@ -0,0 +1,100 @@
+package directory
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Filter is a SCIM filter expression, built with Eq, Co, Sw, And and Or so that
+// values are always quoted and escaped.
+type Filter struct {
+	expr     string
+	compound bool
+}
+
+// Eq matches resources where the attribute equals the value.
+func Eq(attribute string, value string) Filter {
+	return comparison(attribute, "eq", value)
+}
+
+// Co matches resources where the attribute contains the value.
+func Co(attribute string, value string) Filter {
+	return comparison(attribute, "co", value)
+}
+
+// Sw matches resources where the attribute starts with the value.
+func Sw(attribute string, value string) Filter {
+	return comparison(attribute, "sw", value)
+}
+
+// Gt matches resources where the attribute is greater than the value.
+func Gt(attribute string, value string) Filter {
+	return comparison(attribute, "gt", value)
+}
+
+// Ge matches resources where the attribute is greater than or equal to the value.
+func Ge(attribute string, value string) Filter {
+	return comparison(attribute, "ge", value)
+}
+
+// Raw wraps an existing filter expression, such as one provided by the user, so
+// that it can be combined with other filters.
+func Raw(expr string) Filter {
+	return Filter{
+		expr:     expr,
+		compound: true,
+	}
+}
+
+// And matches resources that match all of the filters.
+func And(filters ...Filter) Filter {
+	return logical("and", filters)
+}
+
+// Or matches resources that match any of the filters.
+func Or(filters ...Filter) Filter {
+	return logical("or", filters)
+}
+
+// String returns the expression, ready to be set as the filter query parameter.
+func (f Filter) String() string {
+	return f.expr
+}
+
+func comparison(attribute string, operator string, value string) Filter {
+	return Filter{
+		expr: attribute + " " + operator + " " + quoteFilterValue(value),
+	}
+}
+
+func logical(operator string, filters []Filter) Filter {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+
+	exprs := make([]string, 0, len(filters))
+	for _, f := range filters {
+		if f.compound {
+			exprs = append(exprs, "("+f.expr+")")
+		} else {
+			exprs = append(exprs, f.expr)
+		}
+	}
+
+	return Filter{
+		expr:     strings.Join(exprs, " "+operator+" "),
+		compound: len(filters) > 1,
+	}
+}
+
+// quoteFilterValue quotes the value as a JSON string, as required for SCIM filter
+// values, escaping quotes, backslashes and control characters.
+func quoteFilterValue(value string) string {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,77 @@
+package directory
+
+import (
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{
+			name:   "plain value",
+			filter: Eq("displayName", "Sales"),
+			want:   `displayName eq "Sales"`,
+		},
+		{
+			name:   "spaces",
+			filter: Co("displayName", "Sales Team EMEA"),
+			want:   `displayName co "Sales Team EMEA"`,
+		},
+		{
+			name:   "quotes",
+			filter: Eq("displayName", `My "Special" Group`),
+			want:   `displayName eq "My \"Special\" Group"`,
+		},
+		{
+			name:   "backslash",
+			filter: Sw("displayName", `dom\grp`),
+			want:   `displayName sw "dom\\grp"`,
+		},
+		{
+			name:   "unicode",
+			filter: Eq("displayName", "Ventes équipe 営業"),
+			want:   `displayName eq "Ventes équipe 営業"`,
+		},
+		{
+			name:   "html characters are not escaped",
+			filter: Eq("displayName", "R&D <core>"),
+			want:   `displayName eq "R&D <core>"`,
+		},
+		{
+			name:   "injection attempt",
+			filter: Eq("displayName", `x" or displayName pr or displayName eq "y`),
+			want:   `displayName eq "x\" or displayName pr or displayName eq \"y"`,
+		},
+		{
+			name:   "and",
+			filter: And(Eq("displayName", "Sales"), Sw("description", "EMEA")),
+			want:   `displayName eq "Sales" and description sw "EMEA"`,
+		},
+		{
+			name:   "nested or",
+			filter: And(Eq("type", "User"), Or(Eq("userName", "a"), Eq("userName", "b"))),
+			want:   `type eq "User" and (userName eq "a" or userName eq "b")`,
+		},
+		{
+			name:   "raw",
+			filter: And(Raw(`displayName sw "S"`), Ge("meta.lastModified", "2024")),
+			want:   `(displayName sw "S") and meta.lastModified ge "2024"`,
+		},
+		{
+			name:   "single clause",
+			filter: Or(Eq("userName", "a")),
+			want:   `userName eq "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
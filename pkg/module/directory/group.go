@@ -3,26 +3,62 @@ package directory
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ibm-security-verify/verifyctl/pkg/config"
 	"github.com/ibm-security-verify/verifyctl/pkg/module"
 	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+	typesx "github.com/ibm-security-verify/verifyctl/pkg/util/types"
+	"github.com/ibm-security-verify/verifyctl/x/logx"
 )
 
 const (
-	apiGroups = "v2.0/Groups"
+	apiGroups = "Groups"
+
+	coreGroupSchema                = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ibmGroupExtensionSchema        = "urn:ietf:params:scim:schemas:extension:ibm:2.0:Group"
+	ibmNotificationExtensionSchema = "urn:ietf:params:scim:schemas:extension:ibm:2.0:Notification"
+
+	defaultGroupPageSize = 100
+
+	// memberResolveWorkers caps the number of concurrent user lookups made while
+	// resolving group members.
+	memberResolveWorkers = 10
+
+	// forceDeleteAttempts caps the number of times ForceDeleteGroup empties the
+	// group when members are added before it is deleted.
+	forceDeleteAttempts = 3
+)
+
+var (
+	scimPathPattern = regexp.MustCompile(`^[A-Za-z0-9:._$-]+(\[[^\[\]]+\])?(\.[A-Za-z0-9_$-]+)?$`)
+
+	// valueFilterPattern matches a `value eq` clause with a quoted or unquoted operand.
+	// The attribute must be exactly "value" so that clauses such as `display eq` are ignored.
+	valueFilterPattern = regexp.MustCompile(`(?i)(?:^|[\[\s(])value\s+eq\s+(?:"((?:[^"\\]|\\.)+)"|([^\s\]")]+))`)
 )
 
 type GroupClient struct {
+	endpoint
+
 	client xhttp.Clientx
+	users  *UserClient
 }
 
 type GroupListResponse struct {
 	TotalResults int      `json:"totalResults" yaml:"totalResults"`
+	StartIndex   int      `json:"startIndex,omitempty" yaml:"startIndex,omitempty"`
+	ItemsPerPage int      `json:"itemsPerPage,omitempty" yaml:"itemsPerPage,omitempty"`
 	Schemas      []string `json:"schemas" yaml:"schemas"`
 	Groups       []Group  `json:"Resources" yaml:"Resources"`
 }
@@ -37,6 +73,26 @@ type Group struct {
 	IBMGROUP     IBMGROUPExtension `json:"urn:ietf:params:scim:schemas:extension:ibm:2.0:Group,omitempty" yaml:"urn:ietf:params:scim:schemas:extension:ibm:2.0:Group,omitempty"`
 	Notification GroupNotification `json:"urn:ietf:params:scim:schemas:extension:ibm:2.0:Notification,omitempty" yaml:"urn:ietf:params:scim:schemas:extension:ibm:2.0:Notification,omitempty"`
 	Meta         GroupMeta         `json:"meta,omitempty" yaml:"meta,omitempty"`
+
+	// Extensions holds the schema extensions, keyed by URN, that have no field of
+	// their own, so that they are kept when the group is written back.
+	Extensions map[string]json.RawMessage `json:"-" yaml:"-"`
+
+	// AdditionalProperties holds any other attributes that have no field of their
+	// own, such as those added by newer versions of the API.
+	AdditionalProperties map[string]json.RawMessage `json:"-" yaml:"-"`
+
+	// MemberCount is the number of members returned with the group. The list and
+	// read endpoints include members unless they are excluded, such as with
+	// WithExcludedAttributes("members"), in which case it is zero.
+	MemberCount int `json:"-" yaml:"-"`
+
+	// VisibleSet and NotificationSet are true if the visible attribute and the
+	// notification extension were present when the group was decoded. DiffGroups
+	// leaves them unchanged unless they are set on the desired group, so they must
+	// also be set on groups built in code that change them.
+	VisibleSet      bool `json:"-" yaml:"-"`
+	NotificationSet bool `json:"-" yaml:"-"`
 }
 
 type Member struct {
@@ -44,6 +100,10 @@ type Member struct {
 	Value   string `json:"value" yaml:"value"`
 	Display string `json:"display,omitempty" yaml:"display,omitempty"`
 	Ref     string `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+
+	// UserName is set for user members when their display names are resolved, such
+	// as by GetGroupWithMembers.
+	UserName string `json:"-" yaml:"-"`
 }
 
 type IBMGROUPExtension struct {
@@ -66,6 +126,7 @@ type GroupNotification struct {
 type GroupMeta struct {
 	Created      string `json:"created,omitempty" yaml:"created,omitempty"`
 	LastModified string `json:"lastModified,omitempty" yaml:"lastModified,omitempty"`
+	Version      string `json:"version,omitempty" yaml:"version,omitempty"`
 }
 
 type GroupPatchRequest struct {
@@ -84,24 +145,145 @@ type GroupSCIMOpEntry struct {
 	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
 }
 
+// CreateGroupsResult reports the outcome of each group created by CreateGroups.
+type CreateGroupsResult struct {
+	// Created maps the display name of each created group to its URL.
+	Created map[string]string
+	Failed  map[string]error
+}
+
+// Err returns the failures as a single error, or nil if there are none.
+func (r *CreateGroupsResult) Err() error {
+	return joinFailures(r.Failed)
+}
+
+// UpdateGroupResult summarises the membership changes made by UpdateGroupWithResult.
+type UpdateGroupResult struct {
+	Added    int
+	Removed  int
+	Replaced int
+
+	// RemovedMembers holds the IDs of the members removed by a value filter.
+	RemovedMembers []string
+
+	// AllMembersRemoved is set if an operation removed every member of the group,
+	// in which case the number removed is not known.
+	AllMembersRemoved bool
+}
+
+// String describes the changes, such as "added 3, removed 1 members".
+func (r *UpdateGroupResult) String() string {
+	changes := []string{}
+	if r.Added > 0 {
+		changes = append(changes, fmt.Sprintf("added %d", r.Added))
+	}
+
+	if r.AllMembersRemoved {
+		changes = append(changes, "removed all")
+	} else if r.Removed > 0 {
+		changes = append(changes, fmt.Sprintf("removed %d", r.Removed))
+	}
+
+	if r.Replaced > 0 {
+		changes = append(changes, fmt.Sprintf("replaced with %d", r.Replaced))
+	}
+
+	if len(changes) == 0 {
+		return "no members changed"
+	}
+
+	return strings.Join(changes, ", ") + " members"
+}
+
+// DeleteGroupsResult reports the outcome of each deletion made by DeleteGroups.
+type DeleteGroupsResult struct {
+	Deleted []string
+	Failed  map[string]error
+}
+
+// Err returns the failures as a single error, or nil if there are none.
+func (r *DeleteGroupsResult) Err() error {
+	return joinFailures(r.Failed)
+}
+
+// RequestPreview describes a request that would have been sent to the tenant.
+type RequestPreview struct {
+	Method string `json:"method" yaml:"method"`
+	URL    string `json:"url" yaml:"url"`
+	Body   []byte `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
 func NewGroupClient() *GroupClient {
+	return NewGroupClientWithClient(LimitConcurrency(xhttp.NewDefaultClient(), DefaultConcurrencyLimiter))
+}
+
+// NewGroupClientWithClient returns a GroupClient that makes requests, including
+// the user lookups needed to resolve members, using the provided client. Resolved
+// user IDs are cached for the life of the GroupClient.
+func NewGroupClientWithClient(client xhttp.Clientx) *GroupClient {
+	return NewGroupClientWithUserClient(client, NewUserClientWithClient(client))
+}
+
+// NewGroupClientWithUserClient returns a GroupClient that resolves members with
+// the provided UserClient, so that the user ID cache is shared with it. Both
+// clients are safe for concurrent use once configured. The setters of the
+// GroupClient, such as SetBaseURL, also apply to the UserClient.
+func NewGroupClientWithUserClient(client xhttp.Clientx, users *UserClient) *GroupClient {
 	return &GroupClient{
-		client: xhttp.NewDefaultClient(),
+		client: client,
+		users:  users,
 	}
 }
 
-func (c *GroupClient) GetGroup(ctx context.Context, auth *config.AuthConfig, groupName string) (*Group, string, error) {
+// NewClientsWithClient returns a GroupClient and a UserClient that make requests
+// using the provided client and share the cache of resolved user IDs.
+func NewClientsWithClient(client xhttp.Clientx) (*GroupClient, *UserClient) {
+	users := NewUserClientWithClient(client)
+	return NewGroupClientWithUserClient(client, users), users
+}
+
+// SetBaseURL sends requests, including user lookups, to the base URL, such as
+// http://127.0.0.1:8080, instead of https://<tenant>. An empty value restores the default.
+func (c *GroupClient) SetBaseURL(baseURL string) {
+	c.endpoint.SetBaseURL(baseURL)
+	c.users.SetBaseURL(baseURL)
+}
+
+// SetAPIVersion sets the version of the SCIM API used by the client and its user
+// lookups, which defaults to v2.0.
+func (c *GroupClient) SetAPIVersion(version string) {
+	c.endpoint.SetAPIVersion(version)
+	c.users.SetAPIVersion(version)
+}
+
+// SetUserIDCacheEnabled turns the cache of resolved user IDs on or off.
+func (c *GroupClient) SetUserIDCacheEnabled(enabled bool) {
+	c.users.SetCacheEnabled(enabled)
+}
+
+// GetGroup returns the group with the display name. Options such as WithAttributes
+// can be used to trim the response.
+func (c *GroupClient) GetGroup(ctx context.Context, auth *config.AuthConfig, groupName string, opts ...QueryOption) (*Group, string, error) {
 	vc := config.GetVerifyContext(ctx)
 	id, err := c.getGroupId(ctx, auth, groupName)
 	if err != nil {
 		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
 		return nil, "", err
 	}
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s/%s", auth.Tenant, apiGroups, id))
-	headers := http.Header{
-		"Accept":        []string{"application/scim+json"},
-		"Authorization": []string{"Bearer " + auth.Token},
+
+	return c.GetGroupByID(ctx, auth, id, opts...)
+}
+
+// GetGroupByID returns the group with the given ID without looking it up by name.
+func (c *GroupClient) GetGroupByID(ctx context.Context, auth *config.AuthConfig, id string, opts ...QueryOption) (*Group, string, error) {
+	vc := config.GetVerifyContext(ctx)
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups, id))
+	if len(opts) > 0 {
+		q := u.Query()
+		applyQueryOptions(q, opts)
+		u.RawQuery = q.Encode()
 	}
+	headers := scimHeaders(auth)
 
 	response, err := c.client.Get(ctx, u, headers)
 	if err != nil {
@@ -116,37 +298,139 @@ func (c *GroupClient) GetGroup(ctx context.Context, auth *config.AuthConfig, gro
 		}
 
 		vc.Logger.Errorf("unable to get the Group; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return nil, "", fmt.Errorf("unable to get the Group")
+		return nil, "", module.NewAPIError(response, fmt.Errorf("unable to get the Group"))
 	}
 
 	Group := &Group{}
 	if err = json.Unmarshal(response.Body, Group); err != nil {
-		return nil, "", fmt.Errorf("unable to get the Group")
+		return nil, "", fmt.Errorf("unable to get the Group; err=%w", err)
+	}
+
+	if len(Group.Meta.Version) == 0 {
+		Group.Meta.Version = response.Headers.Get("ETag")
 	}
 
 	return Group, u.String(), nil
 }
 
-func (c *GroupClient) GetGroups(ctx context.Context, auth *config.AuthConfig, sort string, count string) (
-	*GroupListResponse, string, error) {
+// GetGroupETag returns the version of the group without fetching the resource. The
+// ETag header of a HEAD request is preferred. If the tenant does not return one, or
+// does not support HEAD, meta.version is read with a minimal GET instead.
+func (c *GroupClient) GetGroupETag(ctx context.Context, auth *config.AuthConfig, groupName string) (string, error) {
+	vc := config.GetVerifyContext(ctx)
+	id, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return "", err
+	}
+
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups, id))
+	headers := scimHeaders(auth)
+
+	response, err := c.client.Head(ctx, u, headers)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the Group version; err=%s", err.Error())
+		return "", err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		if etag := response.Headers.Get("ETag"); len(etag) > 0 {
+			return etag, nil
+		}
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+	default:
+		if err := module.HandleCommonErrors(ctx, response, "unable to get Group"); err != nil {
+			vc.Logger.Errorf("unable to get the Group version; err=%s", err.Error())
+			return "", err
+		}
+
+		vc.Logger.Errorf("unable to get the Group version; code=%d", response.StatusCode)
+		return "", module.NewAPIError(response, fmt.Errorf("unable to get the Group version"))
+	}
+
+	return c.getGroupVersion(ctx, auth, id)
+}
 
+// getGroupVersion reads the version of the group with a GET request that only asks
+// for meta.version. The ETag header is still preferred if the response sets it.
+func (c *GroupClient) getGroupVersion(ctx context.Context, auth *config.AuthConfig, id string) (string, error) {
 	vc := config.GetVerifyContext(ctx)
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s", auth.Tenant, apiGroups))
-	headers := http.Header{
-		"Accept":        []string{"application/scim+json"},
-		"Authorization": []string{"Bearer " + auth.Token},
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups, id))
+	q := u.Query()
+	q.Set("attributes", "meta.version")
+	u.RawQuery = q.Encode()
+	headers := scimHeaders(auth)
+
+	response, err := c.client.Get(ctx, u, headers)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the Group version; err=%s", err.Error())
+		return "", err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if err := module.HandleCommonErrors(ctx, response, "unable to get Group"); err != nil {
+			vc.Logger.Errorf("unable to get the Group version; err=%s", err.Error())
+			return "", err
+		}
+
+		vc.Logger.Errorf("unable to get the Group version; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return "", module.NewAPIError(response, fmt.Errorf("unable to get the Group version"))
+	}
+
+	if etag := response.Headers.Get("ETag"); len(etag) > 0 {
+		return etag, nil
+	}
+
+	group := &Group{}
+	if err := json.Unmarshal(response.Body, group); err != nil {
+		return "", fmt.Errorf("unable to get the Group version; err=%w", err)
 	}
 
+	return group.Meta.Version, nil
+}
+
+// GetGroups returns a page of groups. filter is passed through as the SCIM filter
+// expression, sortOrder is either "ascending" or "descending", and startIndex is
+// the 1-based SCIM index of the first result. Empty values are ignored, leaving the
+// server defaults in place. Options such as WithExcludedAttributes can be used to
+// trim the response.
+func (c *GroupClient) GetGroups(ctx context.Context, auth *config.AuthConfig, filter string, sort string, sortOrder string, count string, startIndex string, opts ...QueryOption) (
+	*GroupListResponse, string, error) {
+
+	vc := config.GetVerifyContext(ctx)
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups))
+	headers := scimHeaders(auth)
+
 	q := u.Query()
 
+	if len(filter) > 0 {
+		q.Set("filter", filter)
+	}
+
 	if len(sort) > 0 {
 		q.Set("sortBy", sort)
 	}
 
+	if len(sortOrder) > 0 {
+		if !strings.EqualFold(sortOrder, "ascending") && !strings.EqualFold(sortOrder, "descending") {
+			return nil, "", fmt.Errorf("invalid sortOrder %s; must be ascending or descending", sortOrder)
+		}
+		q.Set("sortOrder", strings.ToLower(sortOrder))
+	}
+
 	if len(count) > 0 {
 		q.Set("count", count)
 	}
 
+	if len(startIndex) > 0 {
+		if i, err := strconv.Atoi(startIndex); err != nil || i < 1 {
+			return nil, "", fmt.Errorf("invalid startIndex %s; must be an integer greater than or equal to 1", startIndex)
+		}
+		q.Set("startIndex", startIndex)
+	}
+
+	applyQueryOptions(q, opts)
 	if len(q) > 0 {
 		u.RawQuery = q.Encode()
 	}
@@ -165,46 +449,211 @@ func (c *GroupClient) GetGroups(ctx context.Context, auth *config.AuthConfig, so
 		}
 
 		vc.Logger.Errorf("unable to get the Groups; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return nil, "", fmt.Errorf("unable to get the Groups")
+		return nil, "", module.NewAPIError(response, fmt.Errorf("unable to get the Groups"))
 	}
 
 	GroupsResponse := &GroupListResponse{}
 	if err = json.Unmarshal(response.Body, &GroupsResponse); err != nil {
 		vc.Logger.Errorf("unable to get the Groups; err=%s, body=%s", err, string(response.Body))
-		return nil, "", fmt.Errorf("unable to get the Groups")
+		return nil, "", fmt.Errorf("unable to get the Groups; err=%w", err)
 	}
 
 	return GroupsResponse, u.String(), nil
 }
 
-func (c *GroupClient) CreateGroup(ctx context.Context, auth *config.AuthConfig, group *Group) (string, error) {
+// GetGroupCount returns the number of groups matching the filter without fetching
+// the groups themselves.
+func (c *GroupClient) GetGroupCount(ctx context.Context, auth *config.AuthConfig, filter string) (int, error) {
+	page, _, err := c.GetGroups(ctx, auth, filter, "", "", "0", "", WithAttributes("id"))
+	if err != nil {
+		return 0, err
+	}
+
+	// a server that ignores count=0 returns a full page, which may not report the
+	// total, so a single-item page is requested instead
+	if page.TotalResults == 0 && len(page.Groups) > 0 {
+		page, _, err = c.GetGroups(ctx, auth, filter, "", "", "1", "", WithAttributes("id"))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return page.TotalResults, nil
+}
+
+// GetGroupWithMembers returns the group with the display name of each user member
+// resolved. Members that no longer resolve are left without a display name.
+func (c *GroupClient) GetGroupWithMembers(ctx context.Context, auth *config.AuthConfig, groupName string) (*Group, string, error) {
+	group, uri, err := c.GetGroup(ctx, auth, groupName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := c.resolveMemberDisplayNames(ctx, auth, group.Members); err != nil {
+		return nil, "", err
+	}
+
+	return group, uri, nil
+}
+
+// GetGroupMembers returns the members of the group with their display names resolved.
+// When transitive is set, the members of nested groups are included recursively,
+// alongside the nested groups themselves. Each member appears once. If memberType is
+// set to "User" or "Group", only the members of that type are returned; nested
+// groups are still expanded when filtering for users.
+func (c *GroupClient) GetGroupMembers(ctx context.Context, auth *config.AuthConfig, groupName string, transitive bool, memberType string) ([]Member, error) {
+	if len(memberType) > 0 && !strings.EqualFold(memberType, "User") && !isGroupMember(memberType) {
+		return nil, module.MakeSimpleError(fmt.Sprintf("invalid member type %q; expected User or Group", memberType))
+	}
+
+	group, _, err := c.GetGroup(ctx, auth, groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []Member{}
+	seen := map[string]bool{}
+	visited := map[string]bool{
+		group.Id: true,
+	}
+
+	pending := []*Group{group}
+	for len(pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		current := pending[0]
+		pending = pending[1:]
+		for _, m := range current.Members {
+			if !seen[m.Value] {
+				seen[m.Value] = true
+				members = append(members, m)
+			}
+
+			// guard against cycles in the group graph
+			if !transitive || !isGroupMember(m.Type) || visited[m.Value] {
+				continue
+			}
+			visited[m.Value] = true
+
+			nested, _, err := c.GetGroupByID(ctx, auth, m.Value)
+			if err != nil {
+				return nil, fmt.Errorf("unable to expand the nested group %s; err=%w", m.Value, err)
+			}
+			pending = append(pending, nested)
+		}
+	}
+
+	if len(memberType) > 0 {
+		members = slices.DeleteFunc(members, func(m Member) bool {
+			return isGroupMember(m.Type) != isGroupMember(memberType)
+		})
+	}
+
+	if err := c.resolveMemberDisplayNames(ctx, auth, members); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// resolveMemberDisplayNames sets the display name of each user member. Lookup
+// failures are logged and skipped, but an error is returned if the context is
+// cancelled, since the members would be only partly resolved.
+func (c *GroupClient) resolveMemberDisplayNames(ctx context.Context, auth *config.AuthConfig, members []Member) error {
+	vc := config.GetVerifyContext(ctx)
+	client := c.users
+
+	sem := make(chan struct{}, memberResolveWorkers)
+	wg := sync.WaitGroup{}
+	for i := range members {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if members[i].Type != "" && members[i].Type != "User" {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(m *Member) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			user, err := client.getUserByID(ctx, auth, m.Value)
+			if err != nil {
+				vc.Logger.Warnf("unable to resolve the group member %s; err=%s", m.Value, err.Error())
+				return
+			}
+
+			m.UserName = user.UserName
+			if len(user.DisplayName) > 0 {
+				m.Display = user.DisplayName
+			} else {
+				m.Display = user.UserName
+			}
+		}(&members[i])
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+// GetAllGroups follows the SCIM pagination of GetGroups and returns every group
+// on the tenant.
+func (c *GroupClient) GetAllGroups(ctx context.Context, auth *config.AuthConfig, sort string) ([]Group, error) {
+	return c.getAllGroups(ctx, auth, "", sort)
+}
+
+// GetGroupsModifiedSince returns every group modified after the time, for
+// incremental syncs. The time is sent in UTC in the RFC 3339 format of
+// meta.lastModified. ErrUnsupported is returned if the tenant rejects filtering on
+// meta.lastModified.
+func (c *GroupClient) GetGroupsModifiedSince(ctx context.Context, auth *config.AuthConfig, since time.Time) ([]Group, error) {
 	vc := config.GetVerifyContext(ctx)
-	client := NewUserClient()
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s", auth.Tenant, apiGroups))
-	headers := http.Header{
-		"Accept":                            []string{"application/scim+json"},
-		"Content-Type":                      []string{"application/scim+json"},
-		"groupshouldnotneedtoresetpassword": []string{"false"},
-		"Authorization":                     []string{"Bearer " + auth.Token},
-	}
-
-	for i, m := range group.Members {
-		// Get the username from the member's Value field.
-		username := m.Value
-		// Retrieve the actual user ID using the provided function.
-		userID, err := client.getUserId(ctx, auth, username)
+	filter := Gt("meta.lastModified", since.UTC().Format(time.RFC3339)).String()
+	groups, err := c.getAllGroups(ctx, auth, filter, "")
+	if err != nil {
+		if status := module.StatusCode(err); status == http.StatusBadRequest || status == http.StatusNotImplemented {
+			vc.Logger.Errorf("unable to get the Groups modified since %s; err=%s", since, err.Error())
+			return nil, fmt.Errorf("filtering groups on meta.lastModified is %w; err=%w", ErrUnsupported, err)
+		}
+
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (c *GroupClient) getAllGroups(ctx context.Context, auth *config.AuthConfig, filter string, sort string) ([]Group, error) {
+	groups := []Group{}
+	it := c.IterateGroups(auth, filter, sort)
+	for {
+		group, err := it.Next(ctx)
+		if err == io.EOF {
+			return groups, nil
+		}
+
 		if err != nil {
-			vc.Logger.Errorf("unable to get user ID for username %s; err=%s", username, err.Error())
-			return "", fmt.Errorf("unable to get user ID for username %s; err=%s", username, err.Error())
+			return nil, err
 		}
 
-		// Update the member's Value with the obtained user ID.
-		group.Members[i].Value = userID
+		groups = append(groups, *group)
 	}
+}
 
-	b, err := json.Marshal(group)
+func (c *GroupClient) CreateGroup(ctx context.Context, auth *config.AuthConfig, group *Group) (string, error) {
+	return c.createGroup(ctx, auth, group, nil)
+}
+
+func (c *GroupClient) createGroup(ctx context.Context, auth *config.AuthConfig, group *Group, userIDs map[string]string) (string, error) {
+	vc := config.GetVerifyContext(ctx)
+	u, headers, b, err := c.buildCreateGroupRequest(ctx, auth, group, userIDs)
 	if err != nil {
-		vc.Logger.Errorf("Unable to marshal group data; err=%v", err)
 		return "", err
 	}
 
@@ -215,156 +664,994 @@ func (c *GroupClient) CreateGroup(ctx context.Context, auth *config.AuthConfig,
 		return "", err
 	}
 
+	if response.StatusCode == http.StatusConflict {
+		vc.Logger.Errorf("Failed to create group; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return "", module.NewAPIError(response, fmt.Errorf("%w with group name %s", ErrGroupExists, group.DisplayName))
+	}
+
 	if response.StatusCode != http.StatusCreated {
 		vc.Logger.Errorf("Failed to create group; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return "", fmt.Errorf("Failed to create group; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return "", module.NewAPIError(response, fmt.Errorf("Failed to create group; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body))))
 	}
 
 	m := map[string]interface{}{}
 	if err := json.Unmarshal(response.Body, &m); err != nil {
-		return "", fmt.Errorf("Failed to parse response")
+		return "", fmt.Errorf("Failed to parse response; err=%w", err)
+	}
+
+	id := typesx.Map(m).SafeString("id", "")
+	if len(id) == 0 {
+		if location := response.Headers.Get("Location"); len(location) > 0 {
+			return location, nil
+		}
+
+		vc.Logger.Errorf("Group created but the response has no valid 'id'; body=%s", string(response.Body))
+		return "", fmt.Errorf("group created but the response does not contain a valid 'id'")
 	}
 
-	id := m["id"].(string)
-	return fmt.Sprintf("https://%s/%s/%s", auth.Tenant, apiGroups, id), nil
+	return c.resourceURL(auth, apiGroups, id), nil
 }
 
-func (c *GroupClient) DeleteGroup(ctx context.Context, auth *config.AuthConfig, groupName string) error {
+// CreateGroups creates each group in turn and reports which were created and which
+// failed. The user members of all the groups are resolved together up front, so a
+// user that belongs to several groups is only looked up once. A failure does not
+// stop the remaining groups from being created. Groups that share a display name are
+// not created and fail with ErrDuplicateGroup, since the results are keyed by name.
+func (c *GroupClient) CreateGroups(ctx context.Context, auth *config.AuthConfig, groups []*Group) *CreateGroupsResult {
 	vc := config.GetVerifyContext(ctx)
+	result := &CreateGroupsResult{
+		Created: map[string]string{},
+		Failed:  map[string]error{},
+	}
 
-	id, err := c.getGroupId(ctx, auth, groupName)
-	if err != nil {
-		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
-		return fmt.Errorf("unable to get the group ID; err=%s", err.Error())
+	counts := map[string]int{}
+	for _, group := range groups {
+		counts[group.DisplayName]++
 	}
 
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s/%s", auth.Tenant, apiGroups, id))
-	headers := http.Header{
-		"Content-Type":  []string{"application/json"},
-		"Authorization": []string{"Bearer " + auth.Token},
+	unique := []*Group{}
+	for _, group := range groups {
+		if counts[group.DisplayName] > 1 {
+			result.Failed[group.DisplayName] = ErrDuplicateGroup
+			continue
+		}
+
+		unique = append(unique, group)
 	}
 
-	response, err := c.client.Delete(ctx, u, headers)
-	if err != nil {
-		vc.Logger.Errorf("unable to delete the Group; err=%s", err.Error())
-		return fmt.Errorf("unable to delete the Group; err=%s", err.Error())
+	usernames := []string{}
+	for _, group := range unique {
+		for _, m := range group.Members {
+			if !isGroupMember(m.Type) {
+				usernames = append(usernames, m.Value)
+			}
+		}
 	}
 
-	if response.StatusCode != http.StatusNoContent {
-		if err := module.HandleCommonErrors(ctx, response, "unable to delete Group"); err != nil {
-			vc.Logger.Errorf("unable to delete the Group; err=%s", err.Error())
-			return fmt.Errorf("unable to delete the Group; err=%s", err.Error())
+	// users that cannot be resolved are retried, and reported, by the groups that
+	// contain them
+	userIDs := map[string]string{}
+	if len(usernames) > 0 {
+		ids, err := c.users.BatchResolveUserIDs(ctx, auth, usernames)
+		if err != nil {
+			vc.Logger.Warnf("unable to get all the user IDs for the group members; err=%s", err.Error())
 		}
 
-		vc.Logger.Errorf("unable to delete the Group; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return fmt.Errorf("unable to delete the Group")
+		if ids != nil {
+			userIDs = ids
+		}
 	}
 
-	return nil
+	for _, group := range unique {
+		if err := ctx.Err(); err != nil {
+			result.Failed[group.DisplayName] = err
+			continue
+		}
+
+		resourceURI, err := c.createGroup(ctx, auth, group, userIDs)
+		if err != nil {
+			result.Failed[group.DisplayName] = err
+			continue
+		}
+
+		result.Created[group.DisplayName] = resourceURI
+	}
+
+	return result
 }
 
-func (c *GroupClient) UpdateGroup(ctx context.Context, auth *config.AuthConfig, groupName string, operations []GroupSCIMOpEntry) error {
-	vc := config.GetVerifyContext(ctx)
-	client := NewUserClient()
-	groupID, err := c.getGroupId(ctx, auth, groupName)
-	if err != nil {
-		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
-		return fmt.Errorf("unable to get the group ID; err=%s", err.Error())
+// CreateGroupIfNotExists creates the group unless one with the same display name
+// already exists. It returns the URL of the group and whether it was created.
+func (c *GroupClient) CreateGroupIfNotExists(ctx context.Context, auth *config.AuthConfig, group *Group) (string, bool, error) {
+	id, err := c.getGroupId(ctx, auth, group.DisplayName)
+	if err == nil {
+		return c.resourceURL(auth, apiGroups, id), false, nil
 	}
 
-	for i, op := range operations {
-		if op.Op == "add" && op.Path == "members" {
-			if values, ok := op.Value.([]interface{}); ok {
-				for j, v := range values {
-					if member, ok := v.(map[string]interface{}); ok {
-						if username, exists := member["value"].(string); exists {
-							userID, err := client.getUserId(ctx, auth, username)
-							if err != nil {
-								vc.Logger.Errorf("unable to get user ID for username %s; err=%s", username, err.Error())
-								return fmt.Errorf("unable to get user ID for username %s; err=%s", username, err.Error())
-							}
-							operations[i].Value.([]interface{})[j].(map[string]interface{})["value"] = userID
-						}
-					}
-				}
-			}
-		} else if op.Op == "remove" {
-			username := extractUsernameFromPath(op.Path)
-			if username != "" {
-				userID, err := client.getUserId(ctx, auth, username)
-				if err != nil {
-					vc.Logger.Errorf("unable to get user ID for username %s; err=%s", username, err.Error())
-					return fmt.Errorf("unable to get user ID for username %s; err=%s", username, err.Error())
-				}
-				operations[i].Path = fmt.Sprintf("members[value eq \"%s\"]", userID)
-			}
-		}
+	if !errors.Is(err, ErrGroupNotFound) {
+		return "", false, err
 	}
 
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s/%s", auth.Tenant, apiGroups, groupID))
-	headers := http.Header{
-		"Accept":        []string{"application/scim+json"},
-		"Content-Type":  []string{"application/scim+json"},
-		"Authorization": []string{"Bearer " + auth.Token},
+	resourceURI, err := c.CreateGroup(ctx, auth, group)
+	if err == nil {
+		return resourceURI, true, nil
 	}
 
-	patchRequest := GroupSCIMPatchRequest{
-		Schemas:    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
-		Operations: operations,
+	if !errors.Is(err, ErrGroupExists) {
+		return "", false, err
 	}
 
-	b, err := json.Marshal(patchRequest)
+	// the group was created after the lookup
+	id, err = c.getGroupId(ctx, auth, group.DisplayName)
 	if err != nil {
-		vc.Logger.Errorf("unable to marshal the patch request; err=%v", err)
-		return fmt.Errorf("unable to marshal the patch request; err=%v", err)
+		return "", false, err
 	}
 
-	response, err := c.client.Patch(ctx, u, headers, b)
+	return c.resourceURL(auth, apiGroups, id), false, nil
+}
+
+// DryRunCreateGroup resolves the group members and returns the request CreateGroup
+// would send without creating the group.
+func (c *GroupClient) DryRunCreateGroup(ctx context.Context, auth *config.AuthConfig, group *Group) (*RequestPreview, error) {
+	u, _, b, err := c.buildCreateGroupRequest(ctx, auth, group, nil)
 	if err != nil {
-		vc.Logger.Errorf("unable to update group; err=%v", err)
-		return fmt.Errorf("unable to update group; err=%v", err)
-	}
-	if response.StatusCode != http.StatusNoContent {
-		vc.Logger.Errorf("failed to update group; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return fmt.Errorf("failed to update group ; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return nil, err
 	}
 
-	return nil
+	return &RequestPreview{
+		Method: http.MethodPost,
+		URL:    u.String(),
+		Body:   b,
+	}, nil
 }
 
-func (c *GroupClient) getGroupId(ctx context.Context, auth *config.AuthConfig, name string) (string, error) {
+func (c *GroupClient) buildCreateGroupRequest(ctx context.Context, auth *config.AuthConfig, group *Group, userIDs map[string]string) (*url.URL, http.Header, []byte, error) {
 	vc := config.GetVerifyContext(ctx)
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups))
+	headers := scimBodyHeaders(auth)
 
-	headers := http.Header{
-		"Accept":        []string{"application/scim+json"},
-		"Authorization": []string{"Bearer " + auth.Token},
+	if len(group.Schemas) == 0 {
+		group.Schemas = defaultGroupSchemas(group)
 	}
 
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s", auth.Tenant, apiGroups))
-	q := u.Query()
-	q.Set("filter", fmt.Sprintf(`displayName eq "%s"`, name))
-	u.RawQuery = q.Encode()
+	if err := group.Validate(); err != nil {
+		vc.Logger.Errorf("invalid group; err=%s", err.Error())
+		return nil, nil, nil, err
+	}
 
-	response, _ := c.client.Get(ctx, u, headers)
-	if response.StatusCode != http.StatusOK {
-		if err := module.HandleCommonErrors(ctx, response, "unable to get Group"); err != nil {
-			vc.Logger.Errorf("unable to get the Group with groupName %s; err=%s", name, err.Error())
-			return "", fmt.Errorf("unable to get the Group with groupName %s; err=%s", name, err.Error())
-		}
+	if err := c.resolveMembers(ctx, auth, group.Members, userIDs); err != nil {
+		return nil, nil, nil, err
 	}
 
-	var data map[string]interface{}
-	if err := json.Unmarshal(response.Body, &data); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	b, err := json.Marshal(group)
+	if err != nil {
+		vc.Logger.Errorf("Unable to marshal group data; err=%v", err)
+		return nil, nil, nil, err
 	}
 
-	resources, ok := data["Resources"].([]interface{})
-	if !ok || len(resources) == 0 {
-		return "", fmt.Errorf("no group found with group name %s", name)
+	return u, headers, b, nil
+}
+
+// Validate checks that the group has a display name and schemas, and that every
+// member has a value. All the problems found are reported in a single error.
+func (g *Group) Validate() error {
+	problems := []string{}
+	if len(g.DisplayName) == 0 {
+		problems = append(problems, "displayName is required")
 	}
 
-	firstResource, ok := resources[0].(map[string]interface{})
-	if !ok {
+	if len(g.Schemas) == 0 {
+		problems = append(problems, "schemas is required")
+	}
+
+	for i, m := range g.Members {
+		if len(m.Value) == 0 {
+			problems = append(problems, fmt.Sprintf("member %d has no value", i))
+		}
+	}
+
+	if len(problems) > 0 {
+		return module.MakeSimpleError("invalid group; " + strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// defaultGroupSchemas returns the schemas of the group based on the extensions it uses.
+func defaultGroupSchemas(group *Group) []string {
+	schemas := []string{coreGroupSchema}
+	if len(group.IBMGROUP.Description) > 0 || len(group.IBMGROUP.Owners) > 0 {
+		schemas = append(schemas, ibmGroupExtensionSchema)
+	}
+
+	return schemas
+}
+
+// resolveMembers replaces the member values with resource IDs. The value of a
+// member with type "Group" is the display name of the nested group and any other
+// member value is a username. Usernames found in knownIDs are not looked up again.
+func (c *GroupClient) resolveMembers(ctx context.Context, auth *config.AuthConfig, members []Member, knownIDs map[string]string) error {
+	vc := config.GetVerifyContext(ctx)
+	usernames := []string{}
+	for i, m := range members {
+		if !isGroupMember(m.Type) {
+			if _, ok := knownIDs[m.Value]; !ok {
+				usernames = append(usernames, m.Value)
+			}
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		groupID, err := c.getGroupId(ctx, auth, m.Value)
+		if err != nil {
+			vc.Logger.Errorf("unable to get the group ID for the nested group %s; err=%s", m.Value, err.Error())
+			return fmt.Errorf("unable to get the group ID for the nested group %s; err=%w", m.Value, err)
+		}
+		members[i].Value = groupID
+	}
+
+	userIDs := map[string]string{}
+	if len(usernames) > 0 {
+		ids, err := c.users.BatchResolveUserIDs(ctx, auth, usernames)
+		if err != nil {
+			vc.Logger.Errorf("unable to get user IDs for the group members; err=%s", err.Error())
+			return fmt.Errorf("unable to get user IDs for the group members; err=%w", err)
+		}
+		userIDs = ids
+	}
+
+	for i, m := range members {
+		if isGroupMember(m.Type) {
+			continue
+		}
+
+		if id, ok := knownIDs[m.Value]; ok {
+			members[i].Value = id
+		} else {
+			members[i].Value = userIDs[m.Value]
+		}
+	}
+
+	return nil
+}
+
+func isGroupMember(memberType string) bool {
+	return strings.EqualFold(memberType, "Group")
+}
+
+func (c *GroupClient) DeleteGroup(ctx context.Context, auth *config.AuthConfig, groupName string) error {
+	vc := config.GetVerifyContext(ctx)
+
+	id, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return fmt.Errorf("unable to get the group ID; err=%w", err)
+	}
+
+	return c.DeleteGroupByID(ctx, auth, id)
+}
+
+// DeleteGroupByID deletes the group with the given ID without looking it up by name.
+func (c *GroupClient) DeleteGroupByID(ctx context.Context, auth *config.AuthConfig, id string) error {
+	vc := config.GetVerifyContext(ctx)
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups, id))
+	headers := scimHeaders(auth)
+
+	response, err := c.client.Delete(ctx, u, headers)
+	if err != nil {
+		vc.Logger.Errorf("unable to delete the Group; err=%s", err.Error())
+		return fmt.Errorf("unable to delete the Group; err=%w", err)
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		vc.Logger.Errorf("unable to delete the Group; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return module.NewAPIError(response, fmt.Errorf("unable to delete the Group; %w with ID %s", ErrGroupNotFound, id))
+	}
+
+	if response.StatusCode != http.StatusNoContent {
+		if err := module.HandleCommonErrors(ctx, response, "unable to delete Group"); err != nil {
+			vc.Logger.Errorf("unable to delete the Group; err=%s", err.Error())
+			return fmt.Errorf("unable to delete the Group; err=%w", err)
+		}
+
+		vc.Logger.Errorf("unable to delete the Group; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return module.NewAPIError(response, fmt.Errorf("unable to delete the Group"))
+	}
+
+	return nil
+}
+
+// ForceDeleteGroup removes every member of the group and then deletes it, for
+// tenants that refuse to delete a group that has members. It returns the number
+// of members removed. If members are added between the two requests and the
+// deletion is rejected, the group is emptied again, up to forceDeleteAttempts
+// times.
+func (c *GroupClient) ForceDeleteGroup(ctx context.Context, auth *config.AuthConfig, groupName string) (int, error) {
+	vc := config.GetVerifyContext(ctx)
+	id, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return 0, fmt.Errorf("unable to get the group ID; err=%w", err)
+	}
+
+	removed := 0
+	for attempt := 1; ; attempt++ {
+		group, _, err := c.GetGroupByID(ctx, auth, id, WithAttributes("members.value"))
+		if err != nil {
+			return removed, err
+		}
+
+		if len(group.Members) > 0 {
+			if err := c.patchGroup(ctx, auth, id, clearMembersOperations()); err != nil {
+				return removed, err
+			}
+			removed += len(group.Members)
+		}
+
+		err = c.DeleteGroupByID(ctx, auth, id)
+		if err == nil || attempt == forceDeleteAttempts || !isMembershipConflict(err) {
+			return removed, err
+		}
+
+		vc.Logger.Warnf("the group was not deleted and is emptied again; attempt=%d, err=%s", attempt, err.Error())
+	}
+}
+
+// isMembershipConflict reports whether a deletion may have been rejected because
+// the group has members.
+func isMembershipConflict(err error) bool {
+	code := module.StatusCode(err)
+	return code == http.StatusBadRequest || code == http.StatusConflict
+}
+
+// DeleteGroups attempts to delete every named group, running up to concurrency
+// deletions at a time, and reports which succeeded and which failed. Failures do
+// not stop the remaining deletions.
+func (c *GroupClient) DeleteGroups(ctx context.Context, auth *config.AuthConfig, groupNames []string, concurrency int) *DeleteGroupsResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	result := &DeleteGroupsResult{
+		Deleted: []string{},
+		Failed:  map[string]error{},
+	}
+
+	mu := sync.Mutex{}
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for _, name := range groupNames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := ctx.Err()
+			if err == nil {
+				err = c.DeleteGroup(ctx, auth, name)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed[name] = err
+			} else {
+				result.Deleted = append(result.Deleted, name)
+			}
+		}(name)
+	}
+
+	wg.Wait()
+	return result
+}
+
+// DryRunDeleteGroup resolves the group and returns the request DeleteGroup would
+// send without deleting the group.
+func (c *GroupClient) DryRunDeleteGroup(ctx context.Context, auth *config.AuthConfig, groupName string) (*RequestPreview, error) {
+	vc := config.GetVerifyContext(ctx)
+	id, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return nil, fmt.Errorf("unable to get the group ID; err=%w", err)
+	}
+
+	return &RequestPreview{
+		Method: http.MethodDelete,
+		URL:    c.resourceURL(auth, apiGroups, id),
+	}, nil
+}
+
+// UpdateGroup applies the operations to the group in a single PATCH request, which
+// the tenant applies atomically. If it fails, a *PatchError identifies the failing
+// operation where the tenant reports it.
+func (c *GroupClient) UpdateGroup(ctx context.Context, auth *config.AuthConfig, groupName string, operations []GroupSCIMOpEntry) error {
+	_, err := c.UpdateGroupWithResult(ctx, auth, groupName, operations)
+	return err
+}
+
+// UpdateGroupWithResult applies the operations as UpdateGroup does and returns a
+// summary of the membership changes they made.
+func (c *GroupClient) UpdateGroupWithResult(ctx context.Context, auth *config.AuthConfig, groupName string, operations []GroupSCIMOpEntry) (*UpdateGroupResult, error) {
+	vc := config.GetVerifyContext(ctx)
+	if err := validateGroupOperations(operations); err != nil {
+		return nil, err
+	}
+
+	groupID, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return nil, fmt.Errorf("unable to get the group ID; err=%w", err)
+	}
+
+	if err := c.updateGroupByID(ctx, auth, groupID, operations); err != nil {
+		return nil, err
+	}
+
+	// the operations now carry the resolved user IDs
+	return summarizeGroupOperations(operations), nil
+}
+
+// UpdateGroupByID applies the operations to the group with the given ID. Usernames
+// in member operations are resolved to user IDs as with UpdateGroup.
+func (c *GroupClient) UpdateGroupByID(ctx context.Context, auth *config.AuthConfig, groupID string, operations []GroupSCIMOpEntry) error {
+	if err := validateGroupOperations(operations); err != nil {
+		return err
+	}
+
+	return c.updateGroupByID(ctx, auth, groupID, operations)
+}
+
+// updateGroupByID is UpdateGroupByID for operations that have been validated.
+func (c *GroupClient) updateGroupByID(ctx context.Context, auth *config.AuthConfig, groupID string, operations []GroupSCIMOpEntry) error {
+	if err := c.resolveOperationUsers(ctx, auth, operations); err != nil {
+		return err
+	}
+
+	return c.patchGroup(ctx, auth, groupID, operations)
+}
+
+// DryRunUpdateGroup resolves the group and the usernames in the operations and
+// returns the request UpdateGroup would send without updating the group.
+func (c *GroupClient) DryRunUpdateGroup(ctx context.Context, auth *config.AuthConfig, groupName string, operations []GroupSCIMOpEntry) (*RequestPreview, error) {
+	vc := config.GetVerifyContext(ctx)
+	if err := validateGroupOperations(operations); err != nil {
+		return nil, err
+	}
+
+	groupID, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return nil, fmt.Errorf("unable to get the group ID; err=%w", err)
+	}
+
+	if err := c.resolveOperationUsers(ctx, auth, operations); err != nil {
+		return nil, err
+	}
+
+	u, _, b, err := c.buildPatchGroupRequest(ctx, auth, groupID, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestPreview{
+		Method: http.MethodPatch,
+		URL:    u.String(),
+		Body:   b,
+	}, nil
+}
+
+// summarizeGroupOperations counts the members added, removed and replaced by the
+// operations.
+func summarizeGroupOperations(operations []GroupSCIMOpEntry) *UpdateGroupResult {
+	result := &UpdateGroupResult{}
+	for _, op := range operations {
+		if !strings.HasPrefix(strings.ToLower(op.Path), "members") {
+			continue
+		}
+
+		switch op.Op {
+		case "add":
+			if values, ok := op.Value.([]interface{}); ok {
+				result.Added += len(values)
+			}
+		case "replace":
+			if values, ok := op.Value.([]interface{}); ok {
+				result.Replaced += len(values)
+			}
+		case "remove":
+			if id := extractUsernameFromPath(op.Path); len(id) > 0 {
+				result.Removed++
+				result.RemovedMembers = append(result.RemovedMembers, id)
+			} else if strings.EqualFold(op.Path, "members") {
+				result.AllMembersRemoved = true
+			}
+		}
+	}
+
+	return result
+}
+
+// validateGroupOperations checks that each operation has a supported op, a well-formed
+// path and a value that matches the op.
+func validateGroupOperations(operations []GroupSCIMOpEntry) error {
+	if len(operations) == 0 {
+		return module.MakeSimpleError("at least one operation is required")
+	}
+
+	for i, op := range operations {
+		if len(op.Path) > 0 && !scimPathPattern.MatchString(op.Path) {
+			return module.MakeSimpleError(fmt.Sprintf("operation %d has an invalid path %q", i, op.Path))
+		}
+
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return module.MakeSimpleError(fmt.Sprintf("operation %d (%s) requires a value", i, op.Op))
+			}
+
+			// without a path, the value must be an object of attributes
+			if len(op.Path) == 0 {
+				if _, ok := op.Value.(map[string]interface{}); !ok {
+					return module.MakeSimpleError(fmt.Sprintf("operation %d (%s) requires an object value when no path is set", i, op.Op))
+				}
+			}
+
+			if strings.EqualFold(op.Path, "members") {
+				values, ok := op.Value.([]interface{})
+				if !ok {
+					return module.MakeSimpleError(fmt.Sprintf("operation %d (%s) requires a list of members", i, op.Op))
+				}
+
+				for j, v := range values {
+					member, ok := v.(map[string]interface{})
+					if !ok {
+						return module.MakeSimpleError(fmt.Sprintf("operation %d (%s) has an invalid member at index %d", i, op.Op, j))
+					}
+
+					if value, ok := member["value"].(string); !ok || len(value) == 0 {
+						return module.MakeSimpleError(fmt.Sprintf("operation %d (%s) has a member without a value at index %d", i, op.Op, j))
+					}
+				}
+			}
+		case "remove":
+			if len(op.Path) == 0 {
+				return module.MakeSimpleError(fmt.Sprintf("operation %d (remove) requires a path", i))
+			}
+		default:
+			return module.MakeSimpleError(fmt.Sprintf("operation %d has an unsupported op %q; expected add, remove or replace", i, op.Op))
+		}
+	}
+
+	return nil
+}
+
+// resolveOperationUsers replaces the usernames in member operations with user IDs
+// and the display names of nested group members with group IDs.
+func (c *GroupClient) resolveOperationUsers(ctx context.Context, auth *config.AuthConfig, operations []GroupSCIMOpEntry) error {
+	vc := config.GetVerifyContext(ctx)
+
+	// collect the usernames so that they can be resolved together
+	usernames := []string{}
+	for _, op := range operations {
+		if values, ok := memberOperationValues(op); ok {
+			for _, v := range values {
+				if member, ok := v.(map[string]interface{}); ok {
+					value, exists := member["value"].(string)
+					if !exists {
+						continue
+					}
+
+					if memberType, _ := member["type"].(string); isGroupMember(memberType) {
+						groupID, err := c.getGroupId(ctx, auth, value)
+						if err != nil {
+							vc.Logger.Errorf("unable to get the group ID for the nested group %s; err=%s", value, err.Error())
+							return fmt.Errorf("unable to get the group ID for the nested group %s; err=%w", value, err)
+						}
+						member["value"] = groupID
+						continue
+					}
+
+					usernames = append(usernames, value)
+				}
+			}
+		} else if username := removedMemberUsername(op); username != "" {
+			usernames = append(usernames, username)
+		}
+	}
+
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	userIDs, err := c.users.BatchResolveUserIDs(ctx, auth, usernames)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the user IDs; err=%s", err.Error())
+		return fmt.Errorf("unable to get the user IDs; err=%w", err)
+	}
+
+	for i, op := range operations {
+		if values, ok := memberOperationValues(op); ok {
+			for _, v := range values {
+				if member, ok := v.(map[string]interface{}); ok {
+					memberType, _ := member["type"].(string)
+					if username, exists := member["value"].(string); exists && !isGroupMember(memberType) {
+						member["value"] = userIDs[username]
+					}
+				}
+			}
+		} else if username := removedMemberUsername(op); username != "" {
+			operations[i].Path = fmt.Sprintf("members[value eq \"%s\"]", userIDs[username])
+		}
+	}
+
+	return nil
+}
+
+// memberOperationValues returns the members of an add or replace operation on the
+// members attribute.
+func memberOperationValues(op GroupSCIMOpEntry) ([]interface{}, bool) {
+	if (op.Op != "add" && op.Op != "replace") || !strings.EqualFold(op.Path, "members") {
+		return nil, false
+	}
+
+	values, ok := op.Value.([]interface{})
+	return values, ok
+}
+
+// removedMemberUsername returns the username filtered on by a remove operation on
+// the members attribute, such as members[value eq "jdoe"], or an empty string.
+func removedMemberUsername(op GroupSCIMOpEntry) string {
+	if op.Op != "remove" || !strings.HasPrefix(strings.ToLower(op.Path), "members[") {
+		return ""
+	}
+
+	return extractUsernameFromPath(op.Path)
+}
+
+// AddGroupMembers adds the users to the group.
+func (c *GroupClient) AddGroupMembers(ctx context.Context, auth *config.AuthConfig, groupName string, usernames []string) error {
+	groupID, userIDs, err := c.resolveGroupAndUsers(ctx, auth, groupName, usernames)
+	if err != nil {
+		return err
+	}
+
+	members := []interface{}{}
+	for _, username := range usernames {
+		members = append(members, map[string]interface{}{
+			"type":  "User",
+			"value": userIDs[username],
+		})
+	}
+
+	return c.patchGroup(ctx, auth, groupID, []GroupSCIMOpEntry{
+		{
+			Op:    "add",
+			Path:  "members",
+			Value: members,
+		},
+	})
+}
+
+// RemoveGroupMembers removes the users from the group.
+func (c *GroupClient) RemoveGroupMembers(ctx context.Context, auth *config.AuthConfig, groupName string, usernames []string) error {
+	groupID, userIDs, err := c.resolveGroupAndUsers(ctx, auth, groupName, usernames)
+	if err != nil {
+		return err
+	}
+
+	operations := []GroupSCIMOpEntry{}
+	for _, username := range usernames {
+		operations = append(operations, GroupSCIMOpEntry{
+			Op:   "remove",
+			Path: fmt.Sprintf("members[value eq \"%s\"]", userIDs[username]),
+		})
+	}
+
+	return c.patchGroup(ctx, auth, groupID, operations)
+}
+
+// SetGroupMembers replaces the members of the group with exactly the users, in a
+// single request. Duplicate usernames are ignored. An empty list removes every
+// member, as ClearGroupMembers does.
+func (c *GroupClient) SetGroupMembers(ctx context.Context, auth *config.AuthConfig, groupName string, usernames []string) error {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, username := range usernames {
+		if !seen[username] {
+			seen[username] = true
+			unique = append(unique, username)
+		}
+	}
+
+	if len(unique) == 0 {
+		return c.ClearGroupMembers(ctx, auth, groupName)
+	}
+
+	groupID, userIDs, err := c.resolveGroupAndUsers(ctx, auth, groupName, unique)
+	if err != nil {
+		return err
+	}
+
+	members := []interface{}{}
+	for _, username := range unique {
+		members = append(members, map[string]interface{}{
+			"type":  "User",
+			"value": userIDs[username],
+		})
+	}
+
+	return c.patchGroup(ctx, auth, groupID, []GroupSCIMOpEntry{
+		{
+			Op:    "replace",
+			Path:  "members",
+			Value: members,
+		},
+	})
+}
+
+// ClearGroupMembers removes every member of the group in a single request,
+// without listing them first.
+func (c *GroupClient) ClearGroupMembers(ctx context.Context, auth *config.AuthConfig, groupName string) error {
+	vc := config.GetVerifyContext(ctx)
+	groupID, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return fmt.Errorf("unable to get the group ID; err=%w", err)
+	}
+
+	return c.patchGroup(ctx, auth, groupID, clearMembersOperations())
+}
+
+// addOwnersOperation adds the owners, and the extension itself if the group has none.
+func addOwnersOperation(hasOwners bool, owners interface{}) GroupSCIMOpEntry {
+	if !hasOwners {
+		return GroupSCIMOpEntry{
+			Op:   "add",
+			Path: ibmGroupExtensionSchema,
+			Value: map[string]interface{}{
+				"owners": owners,
+			},
+		}
+	}
+
+	return GroupSCIMOpEntry{
+		Op:    "add",
+		Path:  ibmGroupExtensionSchema + ":owners",
+		Value: owners,
+	}
+}
+
+// clearMembersOperations returns the operations that remove every member of a group.
+func clearMembersOperations() []GroupSCIMOpEntry {
+	return []GroupSCIMOpEntry{
+		{
+			Op:   "remove",
+			Path: "members",
+		},
+	}
+}
+
+// AddGroupOwners adds the users as owners of the group.
+func (c *GroupClient) AddGroupOwners(ctx context.Context, auth *config.AuthConfig, groupName string, usernames []string) error {
+	groupID, userIDs, err := c.resolveGroupAndUsers(ctx, auth, groupName, usernames)
+	if err != nil {
+		return err
+	}
+
+	group, _, err := c.GetGroupByID(ctx, auth, groupID)
+	if err != nil {
+		return err
+	}
+
+	owners := []interface{}{}
+	for _, username := range usernames {
+		owners = append(owners, map[string]interface{}{
+			"value": userIDs[username],
+		})
+	}
+
+	return c.patchGroup(ctx, auth, groupID, []GroupSCIMOpEntry{addOwnersOperation(len(group.IBMGROUP.Owners) > 0, owners)})
+}
+
+// RemoveGroupOwners removes the users from the owners of the group.
+func (c *GroupClient) RemoveGroupOwners(ctx context.Context, auth *config.AuthConfig, groupName string, usernames []string) error {
+	groupID, userIDs, err := c.resolveGroupAndUsers(ctx, auth, groupName, usernames)
+	if err != nil {
+		return err
+	}
+
+	operations := []GroupSCIMOpEntry{}
+	for _, username := range usernames {
+		operations = append(operations, GroupSCIMOpEntry{
+			Op:   "remove",
+			Path: fmt.Sprintf("%s:owners[value eq \"%s\"]", ibmGroupExtensionSchema, userIDs[username]),
+		})
+	}
+
+	return c.patchGroup(ctx, auth, groupID, operations)
+}
+
+func (c *GroupClient) resolveGroupAndUsers(ctx context.Context, auth *config.AuthConfig, groupName string, usernames []string) (string, map[string]string, error) {
+	vc := config.GetVerifyContext(ctx)
+	if len(usernames) == 0 {
+		return "", nil, module.MakeSimpleError("at least one username is required")
+	}
+
+	groupID, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return "", nil, fmt.Errorf("unable to get the group ID; err=%w", err)
+	}
+
+	userIDs, err := c.users.BatchResolveUserIDs(ctx, auth, usernames)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the user IDs; err=%s", err.Error())
+		return "", nil, fmt.Errorf("unable to get the user IDs; err=%w", err)
+	}
+
+	return groupID, userIDs, nil
+}
+
+func (c *GroupClient) patchGroup(ctx context.Context, auth *config.AuthConfig, groupID string, operations []GroupSCIMOpEntry) error {
+	vc := config.GetVerifyContext(ctx)
+	u, headers, b, err := c.buildPatchGroupRequest(ctx, auth, groupID, operations)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.client.Patch(ctx, u, headers, b)
+	if err != nil {
+		vc.Logger.Errorf("unable to update group; err=%v", err)
+		return fmt.Errorf("unable to update group; err=%w", err)
+	}
+	if response.StatusCode != http.StatusNoContent {
+		if err := module.HandleCommonErrors(ctx, response, "unable to update group"); err != nil {
+			vc.Logger.Errorf("unable to update group; err=%s", err.Error())
+			return newPatchError(operations, err)
+		}
+
+		vc.Logger.Errorf("failed to update group; code=%d, body=%s", response.StatusCode, string(response.Body))
+		var err error = fmt.Errorf("failed to update group ; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body)))
+		if scimErr, ok := module.ParseSCIMError(response.Body); ok {
+			err = fmt.Errorf("failed to update group; code=%d, err=%w", response.StatusCode, scimErr)
+		}
+
+		return newPatchError(operations, module.NewAPIError(response, err))
+	}
+
+	return nil
+}
+
+func (c *GroupClient) buildPatchGroupRequest(ctx context.Context, auth *config.AuthConfig, groupID string, operations []GroupSCIMOpEntry) (*url.URL, http.Header, []byte, error) {
+	vc := config.GetVerifyContext(ctx)
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups, groupID))
+	headers := scimBodyHeaders(auth)
+
+	patchRequest := GroupSCIMPatchRequest{
+		Schemas:    []string{patchRequestSchema},
+		Operations: operations,
+	}
+
+	b, err := json.Marshal(patchRequest)
+	if err != nil {
+		vc.Logger.Errorf("unable to marshal the patch request; err=%v", err)
+		return nil, nil, nil, fmt.Errorf("unable to marshal the patch request; err=%w", err)
+	}
+
+	return u, headers, b, nil
+}
+
+// UpdateGroupDisplayName renames the group. It fails if a group with the new name
+// already exists.
+func (c *GroupClient) UpdateGroupDisplayName(ctx context.Context, auth *config.AuthConfig, oldName string, newName string) error {
+	vc := config.GetVerifyContext(ctx)
+	if len(newName) == 0 {
+		return module.MakeSimpleError("new group name is required")
+	}
+
+	if _, err := c.getGroupId(ctx, auth, newName); err == nil {
+		vc.Logger.Errorf("unable to rename the group %s; a group with name %s already exists", oldName, newName)
+		return fmt.Errorf("unable to rename the group; %w with name %s", ErrGroupExists, newName)
+	} else if !errors.Is(err, ErrGroupNotFound) {
+		return err
+	}
+
+	return c.UpdateGroup(ctx, auth, oldName, []GroupSCIMOpEntry{
+		{
+			Op:    "replace",
+			Path:  "displayName",
+			Value: newName,
+		},
+	})
+}
+
+// UpdateGroupDescription sets the description of the group. An empty description
+// removes it from the group.
+func (c *GroupClient) UpdateGroupDescription(ctx context.Context, auth *config.AuthConfig, groupName string, description string) error {
+	path := ibmGroupExtensionSchema + ":description"
+	builder := NewPatchBuilder()
+	if len(description) == 0 {
+		builder.Remove(path)
+	} else {
+		builder.Replace(path, description)
+	}
+
+	operations, err := builder.Operations()
+	if err != nil {
+		return err
+	}
+
+	return c.UpdateGroup(ctx, auth, groupName, operations)
+}
+
+// UpdateGroupNotification replaces the notification settings of the group. Every
+// setting is sent, including those that are false, so that flags can be turned off.
+func (c *GroupClient) UpdateGroupNotification(ctx context.Context, auth *config.AuthConfig, groupName string, notification GroupNotification) error {
+	operations, err := NewPatchBuilder().
+		Replace(ibmNotificationExtensionSchema, notification).
+		Operations()
+	if err != nil {
+		return err
+	}
+
+	return c.UpdateGroup(ctx, auth, groupName, operations)
+}
+
+func (c *GroupClient) getGroupId(ctx context.Context, auth *config.AuthConfig, name string) (string, error) {
+	vc := config.GetVerifyContext(ctx)
+
+	headers := scimHeaders(auth)
+
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups))
+	q := u.Query()
+	q.Set("filter", Eq("displayName", name).String())
+	u.RawQuery = q.Encode()
+
+	response, err := c.client.Get(ctx, u, headers)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the Group with groupName %s; err=%s", name, err.Error())
+		return "", fmt.Errorf("unable to get the Group with groupName %s; err=%w", name, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if err := module.HandleCommonErrors(ctx, response, "unable to get Group"); err != nil {
+			vc.Logger.Errorf("unable to get the Group with groupName %s; err=%s", name, err.Error())
+			return "", fmt.Errorf("unable to get the Group with groupName %s; err=%w", name, err)
+		}
+
+		vc.Logger.Errorf("unable to get the Group with groupName %s; code=%d, body=%s", name, response.StatusCode, string(response.Body))
+		return "", module.NewAPIError(response, fmt.Errorf("unable to get the Group with groupName %s; code=%d", name, response.StatusCode))
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(response.Body, &data); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	resources, ok := data["Resources"].([]interface{})
+	if !ok || len(resources) == 0 {
+		return "", fmt.Errorf("%w with group name %s", ErrGroupNotFound, name)
+	}
+
+	if len(resources) > 1 {
+		ids := []string{}
+		for _, r := range resources {
+			if m, ok := r.(map[string]interface{}); ok {
+				ids = append(ids, typesx.Map(m).SafeString("id", ""))
+			}
+		}
+
+		return "", fmt.Errorf("%w with group name %s; ids=%s", ErrAmbiguousGroup, name, strings.Join(ids, ", "))
+	}
+
+	firstResource, ok := resources[0].(map[string]interface{})
+	if !ok {
 		return "", fmt.Errorf("invalid resource format")
 	}
 
@@ -376,12 +1663,18 @@ func (c *GroupClient) getGroupId(ctx context.Context, auth *config.AuthConfig, n
 	return id, nil
 }
 
+// extractUsernameFromPath returns the operand of the `value eq` clause in a SCIM
+// path, such as `members[value eq "jdoe"]` or `members[type eq "User" and value eq jdoe]`.
+// An empty string is returned if the path does not filter on value.
 func extractUsernameFromPath(path string) string {
-	re := regexp.MustCompile(`value eq "?([^"]+)"?`)
-	match := re.FindStringSubmatch(path)
+	match := valueFilterPattern.FindStringSubmatch(path)
+	if match == nil {
+		return ""
+	}
 
-	if len(match) > 1 {
-		return match[1]
+	if len(match[1]) > 0 {
+		return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(match[1])
 	}
-	return ""
+
+	return match[2]
 }
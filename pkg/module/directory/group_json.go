@@ -0,0 +1,98 @@
+package directory
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// groupFields is the set of JSON names of the Group fields.
+var groupFields = jsonFieldNames(reflect.TypeOf(Group{}))
+
+// UnmarshalJSON decodes the group, counts its members, records whether the visible
+// attribute and the notification extension are present and collects the attributes
+// that have no field of their own into Extensions and AdditionalProperties.
+func (g *Group) UnmarshalJSON(data []byte) error {
+	type group Group
+	if err := json.Unmarshal(data, (*group)(g)); err != nil {
+		return err
+	}
+
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	g.MemberCount = len(g.Members)
+	_, g.VisibleSet = raw["visible"]
+	_, g.NotificationSet = raw[ibmNotificationExtensionSchema]
+	g.Extensions = nil
+	g.AdditionalProperties = nil
+	for key, value := range raw {
+		if groupFields[key] {
+			continue
+		}
+
+		if strings.HasPrefix(key, "urn:") {
+			if g.Extensions == nil {
+				g.Extensions = map[string]json.RawMessage{}
+			}
+			g.Extensions[key] = value
+		} else {
+			if g.AdditionalProperties == nil {
+				g.AdditionalProperties = map[string]json.RawMessage{}
+			}
+			g.AdditionalProperties[key] = value
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the group along with its Extensions and AdditionalProperties.
+// Neither can override an attribute that has a field of its own.
+func (g Group) MarshalJSON() ([]byte, error) {
+	type group Group
+	b, err := json.Marshal(group(g))
+	if err != nil || (len(g.Extensions) == 0 && len(g.AdditionalProperties) == 0) {
+		return b, err
+	}
+
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	for _, extra := range []map[string]json.RawMessage{g.Extensions, g.AdditionalProperties} {
+		for key, value := range extra {
+			if !groupFields[key] {
+				m[key] = value
+			}
+		}
+	}
+
+	return json.Marshal(m)
+}
+
+// jsonFieldNames returns the JSON names of the exported fields of the struct type.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = field.Name
+		}
+		names[name] = true
+	}
+
+	return names
+}
@@ -0,0 +1,77 @@
+package directory
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGroupRoundTripKeepsUnknownExtensions(t *testing.T) {
+	input := `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group", "urn:example:params:scim:schemas:extension:acme:1.0:Group"],
+		"id": "g1",
+		"displayName": "Sales",
+		"urn:ietf:params:scim:schemas:extension:ibm:2.0:Group": {"description": "Sales team"},
+		"urn:example:params:scim:schemas:extension:acme:1.0:Group": {"costCenter": "CC-42", "region": ["emea", "apac"]},
+		"futureAttribute": {"enabled": true}
+	}`
+
+	group := &Group{}
+	if err := json.Unmarshal([]byte(input), group); err != nil {
+		t.Fatalf("Unmarshal() err = %v, want nil", err)
+	}
+
+	if _, ok := group.Extensions["urn:example:params:scim:schemas:extension:acme:1.0:Group"]; !ok {
+		t.Errorf("Extensions = %v, want the acme extension", group.Extensions)
+	}
+
+	if _, ok := group.Extensions["urn:ietf:params:scim:schemas:extension:ibm:2.0:Group"]; ok {
+		t.Error("Extensions holds the IBM extension, which has a field of its own")
+	}
+
+	if _, ok := group.AdditionalProperties["futureAttribute"]; !ok {
+		t.Errorf("AdditionalProperties = %v, want futureAttribute", group.AdditionalProperties)
+	}
+
+	b, err := json.Marshal(group)
+	if err != nil {
+		t.Fatalf("Marshal() err = %v, want nil", err)
+	}
+
+	var got, want map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unable to decode the marshalled group; err=%v", err)
+	}
+	if err := json.Unmarshal([]byte(input), &want); err != nil {
+		t.Fatalf("unable to decode the input; err=%v", err)
+	}
+
+	for _, key := range []string{"urn:example:params:scim:schemas:extension:acme:1.0:Group", "futureAttribute", "displayName", "urn:ietf:params:scim:schemas:extension:ibm:2.0:Group"} {
+		if !reflect.DeepEqual(got[key], want[key]) {
+			t.Errorf("%s = %v, want %v", key, got[key], want[key])
+		}
+	}
+}
+
+func TestGroupExtensionsCannotOverrideFields(t *testing.T) {
+	group := &Group{
+		DisplayName: "Sales",
+		AdditionalProperties: map[string]json.RawMessage{
+			"displayName": json.RawMessage(`"Other"`),
+		},
+	}
+
+	b, err := json.Marshal(group)
+	if err != nil {
+		t.Fatalf("Marshal() err = %v, want nil", err)
+	}
+
+	decoded := &Group{}
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatalf("Unmarshal() err = %v, want nil", err)
+	}
+
+	if decoded.DisplayName != "Sales" {
+		t.Errorf("displayName = %s, want Sales", decoded.DisplayName)
+	}
+}
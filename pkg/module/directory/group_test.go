@@ -0,0 +1,559 @@
+package directory
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+func TestExtractUsernameFromPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "quoted",
+			path: `value eq "jdoe"`,
+			want: "jdoe",
+		},
+		{
+			name: "unquoted",
+			path: `value eq jdoe`,
+			want: "jdoe",
+		},
+		{
+			name: "bracketed quoted",
+			path: `members[value eq "jdoe"]`,
+			want: "jdoe",
+		},
+		{
+			name: "bracketed unquoted",
+			path: `members[value eq jdoe]`,
+			want: "jdoe",
+		},
+		{
+			name: "multi-clause with value last",
+			path: `members[type eq "User" and value eq "jdoe"]`,
+			want: "jdoe",
+		},
+		{
+			name: "multi-clause with value first",
+			path: `members[value eq "jdoe" and type eq "User"]`,
+			want: "jdoe",
+		},
+		{
+			name: "parenthesised clause",
+			path: `members[(value eq "jdoe")]`,
+			want: "jdoe",
+		},
+		{
+			name: "case insensitive operator",
+			path: `members[VALUE EQ "jdoe"]`,
+			want: "jdoe",
+		},
+		{
+			name: "escaped quote",
+			path: `members[value eq "j\"doe"]`,
+			want: `j"doe`,
+		},
+		{
+			name: "other attribute",
+			path: `members[display eq "jdoe"]`,
+			want: "",
+		},
+		{
+			name: "attribute ending in value",
+			path: `members[othervalue eq "jdoe"]`,
+			want: "",
+		},
+		{
+			name: "no filter",
+			path: "members",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractUsernameFromPath(tt.path); got != tt.want {
+				t.Errorf("extractUsernameFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGroupETag(t *testing.T) {
+	tests := []struct {
+		name    string
+		head    *xhttp.Response
+		methods []string
+		want    string
+	}{
+		{
+			name:    "ETag from HEAD",
+			head:    &xhttp.Response{StatusCode: http.StatusOK, Headers: http.Header{"Etag": []string{`W/"3"`}}},
+			methods: []string{http.MethodGet, http.MethodHead},
+			want:    `W/"3"`,
+		},
+		{
+			name:    "HEAD without ETag",
+			head:    &xhttp.Response{StatusCode: http.StatusOK, Headers: http.Header{}},
+			methods: []string{http.MethodGet, http.MethodHead, http.MethodGet},
+			want:    "4",
+		},
+		{
+			name:    "HEAD not allowed",
+			head:    &xhttp.Response{StatusCode: http.StatusMethodNotAllowed, Headers: http.Header{}},
+			methods: []string{http.MethodGet, http.MethodHead, http.MethodGet},
+			want:    "4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := onGroupLookup(directorytest.NewFakeClient(), "g1").
+				OnResponse(http.MethodHead, "/v2.0/Groups/g1", tt.head).
+				On(http.MethodGet, "/v2.0/Groups/g1", http.StatusOK, `{"id":"g1","meta":{"version":"4"}}`)
+			client := NewGroupClientWithClient(fake)
+
+			etag, err := client.GetGroupETag(testContext(t), testAuth, "Sales")
+			if err != nil {
+				t.Fatalf("GetGroupETag() err = %v, want nil", err)
+			}
+
+			if etag != tt.want {
+				t.Errorf("GetGroupETag() = %s, want %s", etag, tt.want)
+			}
+
+			methods := []string{}
+			for _, r := range fake.Requests() {
+				methods = append(methods, r.Method)
+			}
+			if !reflect.DeepEqual(methods, tt.methods) {
+				t.Errorf("sent %v, want %v", methods, tt.methods)
+			}
+		})
+	}
+}
+
+func TestCreateGroupWithoutID(t *testing.T) {
+	bodies := map[string]string{
+		"missing id": `{"displayName":"Sales"}`,
+		"numeric id": `{"id":42,"displayName":"Sales"}`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			fake := directorytest.NewFakeClient().
+				On(http.MethodPost, "/v2.0/Groups", http.StatusCreated, body)
+			client := NewGroupClientWithClient(fake)
+
+			_, err := client.CreateGroup(testContext(t), testAuth, &Group{DisplayName: "Sales"})
+			if err == nil || !strings.Contains(err.Error(), "id") {
+				t.Errorf("CreateGroup() err = %v, want an error about the missing id", err)
+			}
+		})
+	}
+}
+
+func TestCreateGroupWithUserAndGroupMembers(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, `{"totalResults":1,"Resources":[{"id":"u1","userName":"jdoe"}]}`).
+		On(http.MethodPost, "/v2.0/Groups", http.StatusCreated, `{"id":"g1"}`)
+	onGroupLookup(fake, "g2")
+	client := NewGroupClientWithClient(fake)
+
+	group := &Group{
+		DisplayName: "Sales",
+		Members: []Member{
+			{Type: "User", Value: "jdoe"},
+			{Type: "Group", Value: "Admins"},
+		},
+	}
+	if _, err := client.CreateGroup(testContext(t), testAuth, group); err != nil {
+		t.Fatalf("CreateGroup() err = %v, want nil", err)
+	}
+
+	var post *directorytest.Request
+	for _, r := range fake.Requests() {
+		switch {
+		case r.Method == http.MethodPost:
+			post = &r
+		case r.URL.Path == "/v2.0/Users":
+			if got := r.URL.Query().Get("filter"); got != `userName eq "jdoe"` {
+				t.Errorf("user lookup filter = %q, want only jdoe", got)
+			}
+		case r.URL.Path == "/v2.0/Groups":
+			if got := r.URL.Query().Get("filter"); got != `displayName eq "Admins"` {
+				t.Errorf("group lookup filter = %q, want only Admins", got)
+			}
+		}
+	}
+
+	if post == nil {
+		t.Fatal("the group was not posted")
+	}
+
+	posted := &Group{}
+	if err := json.Unmarshal(post.Body, posted); err != nil {
+		t.Fatalf("unable to decode the posted group; err=%v", err)
+	}
+
+	want := []Member{
+		{Type: "User", Value: "u1"},
+		{Type: "Group", Value: "g2"},
+	}
+	if !reflect.DeepEqual(posted.Members, want) {
+		t.Errorf("posted members = %+v, want %+v", posted.Members, want)
+	}
+}
+
+func TestCreateGroupsRejectsDuplicateNames(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodPost, "/v2.0/Groups", http.StatusCreated, `{"id":"g1"}`)
+	client := NewGroupClientWithClient(fake)
+
+	result := client.CreateGroups(testContext(t), testAuth, []*Group{
+		{DisplayName: "Sales", Members: []Member{{Type: "Group", Value: "Admins"}}},
+		{DisplayName: "Support"},
+		{DisplayName: "Sales"},
+	})
+
+	if !errors.Is(result.Failed["Sales"], ErrDuplicateGroup) {
+		t.Errorf("Failed[Sales] = %v, want %v", result.Failed["Sales"], ErrDuplicateGroup)
+	}
+
+	if _, ok := result.Created["Support"]; !ok || len(result.Created) != 1 {
+		t.Errorf("Created = %v, want only Support", result.Created)
+	}
+
+	if requests := fake.Requests(); len(requests) != 1 {
+		t.Errorf("%d requests sent, want 1 for Support", len(requests))
+	}
+}
+
+func TestGetGroupIDEscapesDisplayName(t *testing.T) {
+	fake := onGroupLookup(directorytest.NewFakeClient(), "g1")
+	client := NewGroupClientWithClient(fake)
+
+	id, err := client.getGroupId(testContext(t), testAuth, `My "Special" Group`)
+	if err != nil {
+		t.Fatalf("getGroupId() err = %v, want nil", err)
+	}
+
+	if id != "g1" {
+		t.Errorf("getGroupId() = %s, want g1", id)
+	}
+
+	want := `displayName eq "My \"Special\" Group"`
+	if got := fake.Requests()[0].URL.Query().Get("filter"); got != want {
+		t.Errorf("filter = %s, want %s", got, want)
+	}
+}
+
+func TestUpdateGroupNotification(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification GroupNotification
+	}{
+		{name: "enable password", notification: GroupNotification{NotifyType: "EMAIL", NotifyPassword: true}},
+		{name: "disable password", notification: GroupNotification{NotifyType: "EMAIL", NotifyPassword: false, NotifyManager: true}},
+		{name: "enable manager", notification: GroupNotification{NotifyType: "EMAIL", NotifyManager: true}},
+		{name: "disable manager", notification: GroupNotification{NotifyType: "EMAIL", NotifyPassword: true, NotifyManager: false}},
+		{name: "disable both", notification: GroupNotification{NotifyType: "NONE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := onGroupLookup(directorytest.NewFakeClient(), "g1").
+				On(http.MethodPatch, "/v2.0/Groups/g1", http.StatusNoContent, "")
+			client := NewGroupClientWithClient(fake)
+
+			if err := client.UpdateGroupNotification(testContext(t), testAuth, "Sales", tt.notification); err != nil {
+				t.Fatalf("UpdateGroupNotification() err = %v, want nil", err)
+			}
+
+			operations := decodePatch(t, fake.Requests()[1].Body)
+			if len(operations) != 1 || operations[0].Op != "replace" || operations[0].Path != ibmNotificationExtensionSchema {
+				t.Fatalf("operations = %+v, want a single replace of the notification extension", operations)
+			}
+
+			// every flag must be sent, including those that are false
+			want := map[string]interface{}{
+				"notifyType":     tt.notification.NotifyType,
+				"notifyPassword": tt.notification.NotifyPassword,
+				"notifyManager":  tt.notification.NotifyManager,
+			}
+			if !reflect.DeepEqual(operations[0].Value, want) {
+				t.Errorf("value = %v, want %v", operations[0].Value, want)
+			}
+		})
+	}
+}
+
+func TestGroupValidate(t *testing.T) {
+	schemas := []string{coreGroupSchema}
+	tests := []struct {
+		name  string
+		group Group
+		want  []string
+	}{
+		{
+			name:  "valid",
+			group: Group{Schemas: schemas, DisplayName: "Sales", Members: []Member{{Value: "u1"}}},
+		},
+		{
+			name:  "missing displayName",
+			group: Group{Schemas: schemas},
+			want:  []string{"displayName is required"},
+		},
+		{
+			name:  "missing schemas",
+			group: Group{DisplayName: "Sales"},
+			want:  []string{"schemas is required"},
+		},
+		{
+			name:  "member without value",
+			group: Group{Schemas: schemas, DisplayName: "Sales", Members: []Member{{Value: "u1"}, {Type: "User"}}},
+			want:  []string{"member 1 has no value"},
+		},
+		{
+			name:  "every problem",
+			group: Group{Members: []Member{{}}},
+			want:  []string{"displayName is required", "schemas is required", "member 0 has no value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.group.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Validate() = nil, want %v", tt.want)
+			}
+
+			for _, problem := range tt.want {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("Validate() = %q, want it to report %q", err.Error(), problem)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateGroupValidatesBeforeSending(t *testing.T) {
+	fake := directorytest.NewFakeClient()
+	client := NewGroupClientWithClient(fake)
+
+	if _, err := client.CreateGroup(testContext(t), testAuth, &Group{}); err == nil {
+		t.Error("CreateGroup() err = nil, want a validation error")
+	}
+
+	if n := len(fake.Requests()); n != 0 {
+		t.Errorf("sent %d requests, want none", n)
+	}
+}
+
+func TestGetGroupsMemberCount(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":2,"Resources":[
+			{"id":"g1","displayName":"Sales","members":[{"value":"u1"},{"value":"u2"},{"value":"g3","type":"Group"}]},
+			{"id":"g2","displayName":"Empty"}
+		]}`)
+	client := NewGroupClientWithClient(fake)
+
+	groups, _, err := client.GetGroups(testContext(t), testAuth, "", "", "", "", "")
+	if err != nil {
+		t.Fatalf("GetGroups() err = %v, want nil", err)
+	}
+
+	want := map[string]int{"Sales": 3, "Empty": 0}
+	for _, group := range groups.Groups {
+		if group.MemberCount != want[group.DisplayName] {
+			t.Errorf("%s MemberCount = %d, want %d", group.DisplayName, group.MemberCount, want[group.DisplayName])
+		}
+	}
+}
+
+func TestClearGroupMembers(t *testing.T) {
+	fake := onGroupLookup(directorytest.NewFakeClient(), "g1").
+		On(http.MethodPatch, "/v2.0/Groups/g1", http.StatusNoContent, "")
+	client := NewGroupClientWithClient(fake)
+
+	if err := client.ClearGroupMembers(testContext(t), testAuth, "Sales"); err != nil {
+		t.Fatalf("ClearGroupMembers() err = %v, want nil", err)
+	}
+
+	requests := fake.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("sent %d requests, want the lookup and the patch", len(requests))
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(requests[1].Body, &body); err != nil {
+		t.Fatalf("unable to decode the patch request; err=%v", err)
+	}
+
+	want := map[string]interface{}{
+		"schemas": []interface{}{patchRequestSchema},
+		"Operations": []interface{}{
+			map[string]interface{}{"op": "remove", "path": "members"},
+		},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("patch body = %v, want %v", body, want)
+	}
+}
+
+func TestValidateGroupOperations(t *testing.T) {
+	member := func(fields map[string]interface{}) []interface{} {
+		return []interface{}{fields}
+	}
+
+	tests := []struct {
+		name       string
+		operations []GroupSCIMOpEntry
+		wantErr    string
+	}{
+		{
+			name:       "no operations",
+			operations: []GroupSCIMOpEntry{},
+			wantErr:    "at least one operation",
+		},
+		{
+			name:       "invalid path",
+			operations: []GroupSCIMOpEntry{{Op: "remove", Path: `members value eq "jdoe"`}},
+			wantErr:    "invalid path",
+		},
+		{
+			name:       "unsupported op",
+			operations: []GroupSCIMOpEntry{{Op: "move", Path: "members", Value: member(map[string]interface{}{"value": "jdoe"})}},
+			wantErr:    "unsupported op",
+		},
+		{
+			name:       "add without value",
+			operations: []GroupSCIMOpEntry{{Op: "add", Path: "members"}},
+			wantErr:    "requires a value",
+		},
+		{
+			name:       "replace without value",
+			operations: []GroupSCIMOpEntry{{Op: "replace", Path: "displayName"}},
+			wantErr:    "requires a value",
+		},
+		{
+			name:       "no path and a value that is not an object",
+			operations: []GroupSCIMOpEntry{{Op: "replace", Value: "Sales"}},
+			wantErr:    "requires an object value",
+		},
+		{
+			name:       "members that are not a list",
+			operations: []GroupSCIMOpEntry{{Op: "add", Path: "members", Value: "jdoe"}},
+			wantErr:    "requires a list of members",
+		},
+		{
+			name:       "members in another case that are not a list",
+			operations: []GroupSCIMOpEntry{{Op: "add", Path: "Members", Value: "jdoe"}},
+			wantErr:    "requires a list of members",
+		},
+		{
+			name:       "member that is not an object",
+			operations: []GroupSCIMOpEntry{{Op: "replace", Path: "members", Value: []interface{}{"jdoe"}}},
+			wantErr:    "invalid member at index 0",
+		},
+		{
+			name:       "member without a value",
+			operations: []GroupSCIMOpEntry{{Op: "add", Path: "members", Value: member(map[string]interface{}{"type": "User"})}},
+			wantErr:    "member without a value at index 0",
+		},
+		{
+			name:       "member with an empty value",
+			operations: []GroupSCIMOpEntry{{Op: "add", Path: "members", Value: member(map[string]interface{}{"value": ""})}},
+			wantErr:    "member without a value at index 0",
+		},
+		{
+			name:       "member with a value that is not a string",
+			operations: []GroupSCIMOpEntry{{Op: "add", Path: "members", Value: member(map[string]interface{}{"value": 42})}},
+			wantErr:    "member without a value at index 0",
+		},
+		{
+			name:       "remove without path",
+			operations: []GroupSCIMOpEntry{{Op: "remove"}},
+			wantErr:    "requires a path",
+		},
+		{
+			name:       "second operation is invalid",
+			operations: []GroupSCIMOpEntry{{Op: "remove", Path: "members"}, {Op: "remove"}},
+			wantErr:    "operation 1",
+		},
+		{
+			name: "valid",
+			operations: []GroupSCIMOpEntry{
+				{Op: "add", Path: "members", Value: member(map[string]interface{}{"value": "jdoe"})},
+				{Op: "replace", Path: "Members", Value: member(map[string]interface{}{"value": "jdoe", "type": "User"})},
+				{Op: "remove", Path: `members[value eq "jdoe"]`},
+				{Op: "replace", Value: map[string]interface{}{"displayName": "Sales"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGroupOperations(tt.operations)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("validateGroupOperations() = %v, want nil", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateGroupOperations() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveOperationUsers(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, `{"totalResults":1,"Resources":[{"id":"u1","userName":"jdoe"}]}`)
+	client := NewGroupClientWithClient(fake)
+
+	operations := []GroupSCIMOpEntry{
+		{Op: "add", Path: "Members", Value: []interface{}{map[string]interface{}{"value": "jdoe"}}},
+		{Op: "replace", Path: "members", Value: []interface{}{map[string]interface{}{"value": "jdoe", "type": "User"}}},
+		{Op: "remove", Path: `Members[value eq "jdoe"]`},
+		{Op: "remove", Path: `urn:example:params:scim:schemas:extension:acme:1.0:Group:approvers[value eq "jdoe"]`},
+	}
+	if err := client.resolveOperationUsers(testContext(t), testAuth, operations); err != nil {
+		t.Fatalf("resolveOperationUsers() err = %v, want nil", err)
+	}
+
+	for _, i := range []int{0, 1} {
+		value := operations[i].Value.([]interface{})[0].(map[string]interface{})["value"]
+		if value != "u1" {
+			t.Errorf("operation %d (%s %s) member value = %v, want u1", i, operations[i].Op, operations[i].Path, value)
+		}
+	}
+
+	if got, want := operations[2].Path, `members[value eq "u1"]`; got != want {
+		t.Errorf("members remove path = %s, want %s", got, want)
+	}
+
+	// only paths on the members attribute are rewritten
+	if got, want := operations[3].Path, `urn:example:params:scim:schemas:extension:acme:1.0:Group:approvers[value eq "jdoe"]`; got != want {
+		t.Errorf("other remove path = %s, want %s", got, want)
+	}
+}
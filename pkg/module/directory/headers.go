@@ -0,0 +1,28 @@
+package directory
+
+import (
+	"net/http"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+)
+
+const (
+	scimMediaType = "application/scim+json"
+)
+
+// scimHeaders returns the headers of a SCIM request without a body. Every SCIM
+// response, including errors, is sent as application/scim+json. Requests outside the
+// SCIM API, such as the events API used by GetGroupHistory, send their own headers.
+func scimHeaders(auth *config.AuthConfig) http.Header {
+	return http.Header{
+		"Accept":        []string{scimMediaType},
+		"Authorization": []string{"Bearer " + auth.Token},
+	}
+}
+
+// scimBodyHeaders returns the headers of a SCIM request with a body.
+func scimBodyHeaders(auth *config.AuthConfig) http.Header {
+	headers := scimHeaders(auth)
+	headers.Set("Content-Type", scimMediaType)
+	return headers
+}
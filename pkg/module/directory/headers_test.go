@@ -0,0 +1,116 @@
+package directory
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+)
+
+func TestGroupRequestHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(ctx context.Context, client *GroupClient) error
+	}{
+		{
+			name: "GetGroup",
+			call: func(ctx context.Context, client *GroupClient) error {
+				_, _, err := client.GetGroup(ctx, testAuth, "Sales")
+				return err
+			},
+		},
+		{
+			name: "GetGroups",
+			call: func(ctx context.Context, client *GroupClient) error {
+				_, _, err := client.GetGroups(ctx, testAuth, "", "", "", "", "")
+				return err
+			},
+		},
+		{
+			name: "CreateGroup",
+			call: func(ctx context.Context, client *GroupClient) error {
+				_, err := client.CreateGroup(ctx, testAuth, &Group{DisplayName: "Sales"})
+				return err
+			},
+		},
+		{
+			name: "UpdateGroup",
+			call: func(ctx context.Context, client *GroupClient) error {
+				return client.UpdateGroupDescription(ctx, testAuth, "Sales", "Sales team")
+			},
+		},
+		{
+			name: "DeleteGroup",
+			call: func(ctx context.Context, client *GroupClient) error {
+				return client.DeleteGroup(ctx, testAuth, "Sales")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := onGroupLookup(directorytest.NewFakeClient(), "g1").
+				On(http.MethodGet, "/v2.0/Groups/g1", http.StatusOK, `{"id":"g1","displayName":"Sales"}`).
+				On(http.MethodPost, "/v2.0/Groups", http.StatusCreated, `{"id":"g1"}`).
+				On(http.MethodPatch, "/v2.0/Groups/g1", http.StatusNoContent, "").
+				On(http.MethodDelete, "/v2.0/Groups/g1", http.StatusNoContent, "")
+			client := NewGroupClientWithClient(fake)
+
+			if err := tt.call(testContext(t), client); err != nil {
+				t.Fatalf("%s() err = %v, want nil", tt.name, err)
+			}
+
+			for _, r := range fake.Requests() {
+				if got := r.Headers.Get("Accept"); got != scimMediaType {
+					t.Errorf("%s %s Accept = %q, want %q", r.Method, r.URL.Path, got, scimMediaType)
+				}
+
+				if got := r.Headers.Get("Authorization"); got != "Bearer "+testAuth.Token {
+					t.Errorf("%s %s Authorization = %q, want the bearer token", r.Method, r.URL.Path, got)
+				}
+
+				wantContentType := ""
+				if len(r.Body) > 0 {
+					wantContentType = scimMediaType
+				}
+				if got := r.Headers.Get("Content-Type"); got != wantContentType {
+					t.Errorf("%s %s Content-Type = %q, want %q", r.Method, r.URL.Path, got, wantContentType)
+				}
+			}
+		})
+	}
+}
+
+func TestSkipPasswordResetHeader(t *testing.T) {
+	for _, skip := range []bool{false, true} {
+		fake := directorytest.NewFakeClient().
+			On(http.MethodPost, "/v2.0/Users", http.StatusCreated, `{"id":"u1"}`).
+			On(http.MethodGet, "/v2.0/Users", http.StatusOK, `{"totalResults":1,"Resources":[{"id":"u1","userName":"jdoe"}]}`).
+			On(http.MethodPatch, "/v2.0/Users/u1", http.StatusNoContent, "")
+		client := NewUserClientWithClient(fake)
+		client.SetSkipPasswordReset(skip)
+
+		ctx := testContext(t)
+		if _, err := client.CreateUser(ctx, testAuth, &User{UserName: "jdoe"}); err != nil {
+			t.Fatalf("CreateUser() err = %v, want nil", err)
+		}
+
+		operations := []UserSCIMOpEntry{{Op: "replace", Path: "title", Value: "Engineer"}}
+		if err := client.UpdateUser(ctx, testAuth, "jdoe", operations); err != nil {
+			t.Fatalf("UpdateUser() err = %v, want nil", err)
+		}
+
+		want := strconv.FormatBool(skip)
+		for _, request := range fake.Requests() {
+			if request.Method == http.MethodGet {
+				continue
+			}
+
+			if got := request.Headers.Get("usershouldnotneedtoresetpassword"); got != want {
+				t.Errorf("%s %s usershouldnotneedtoresetpassword = %q, want %q", request.Method, request.URL.Path, got, want)
+			}
+		}
+	}
+}
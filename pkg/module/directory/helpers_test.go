@@ -0,0 +1,49 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+var testAuth = &config.AuthConfig{
+	Tenant: "tenant.example.com",
+	Token:  "token",
+}
+
+// testContext returns a context carrying a verify context whose logger discards
+// its output.
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx, err := config.NewContextWithVerifyContext(context.Background(), logx.NewLoggerWithWriter("test", slog.LevelError, io.Discard))
+	if err != nil {
+		t.Fatalf("unable to create the context; err=%v", err)
+	}
+
+	return ctx
+}
+
+// onGroupLookup registers the response to the lookup of a group by name, which
+// finds the group with the ID.
+func onGroupLookup(fake *directorytest.FakeClient, id string) *directorytest.FakeClient {
+	return fake.On("GET", "/v2.0/Groups", 200, `{"totalResults":1,"Resources":[{"id":"`+id+`"}]}`)
+}
+
+// decodePatch returns the operations of the body of a PATCH request.
+func decodePatch(t *testing.T, body []byte) []GroupSCIMOpEntry {
+	t.Helper()
+
+	request := GroupSCIMPatchRequest{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		t.Fatalf("unable to decode the patch request; err=%v", err)
+	}
+
+	return request.Operations
+}
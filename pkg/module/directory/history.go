@@ -0,0 +1,118 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+const (
+	apiEvents = "v1.0/events"
+
+	// maxGroupHistoryEvents is the number of events returned by GetGroupHistory.
+	maxGroupHistoryEvents = 1000
+)
+
+// GroupChangeEvent is a change made to a group, as recorded in the tenant's
+// management events.
+type GroupChangeEvent struct {
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Actor     string    `json:"actor" yaml:"actor"`
+	Operation string    `json:"operation" yaml:"operation"`
+	Result    string    `json:"result,omitempty" yaml:"result,omitempty"`
+}
+
+type eventsResponse struct {
+	Response struct {
+		Events struct {
+			Events []managementEvent `json:"events"`
+		} `json:"events"`
+	} `json:"response"`
+}
+
+type managementEvent struct {
+	Time int64 `json:"time"`
+	Data struct {
+		Action              string `json:"action"`
+		Result              string `json:"result"`
+		PerformedBy         string `json:"performedby"`
+		PerformedByUsername string `json:"performedby_username"`
+	} `json:"data"`
+}
+
+// GetGroupHistory returns the changes made to the group, most recent first, from the
+// management events of the tenant. At most maxGroupHistoryEvents events are returned.
+// ErrUnsupported is returned if the tenant does not provide the events API.
+func (c *GroupClient) GetGroupHistory(ctx context.Context, auth *config.AuthConfig, groupName string) ([]GroupChangeEvent, error) {
+	vc := config.GetVerifyContext(ctx)
+	id, err := c.getGroupId(ctx, auth, groupName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		return nil, err
+	}
+
+	u, _ := url.Parse(c.apiURL(auth, apiEvents))
+	q := u.Query()
+	q.Set("event_type", `"management"`)
+	q.Set("filter_key", "data.targetid")
+	q.Set("filter_value", strconv.Quote(id))
+	q.Set("sort_order", "desc")
+	q.Set("size", strconv.Itoa(maxGroupHistoryEvents))
+	u.RawQuery = q.Encode()
+	headers := http.Header{
+		"Accept":        []string{"application/json"},
+		"Authorization": []string{"Bearer " + auth.Token},
+	}
+
+	response, err := c.client.Get(ctx, u, headers)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the Group history; err=%s", err.Error())
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusNotImplemented {
+		vc.Logger.Errorf("unable to get the Group history; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return nil, module.NewAPIError(response, fmt.Errorf("unable to get the Group history; the events API is %w", ErrUnsupported))
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if err := module.HandleCommonErrors(ctx, response, "unable to get Group history"); err != nil {
+			vc.Logger.Errorf("unable to get the Group history; err=%s", err.Error())
+			return nil, err
+		}
+
+		vc.Logger.Errorf("unable to get the Group history; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return nil, module.NewAPIError(response, fmt.Errorf("unable to get the Group history; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body))))
+	}
+
+	eventsResponse := &eventsResponse{}
+	if err := json.Unmarshal(response.Body, eventsResponse); err != nil {
+		vc.Logger.Errorf("unable to parse the Group history; err=%s, body=%s", err, string(response.Body))
+		return nil, fmt.Errorf("unable to parse the Group history")
+	}
+
+	events := make([]GroupChangeEvent, 0, len(eventsResponse.Response.Events.Events))
+	for _, event := range eventsResponse.Response.Events.Events {
+		actor := event.Data.PerformedByUsername
+		if len(actor) == 0 {
+			actor = event.Data.PerformedBy
+		}
+
+		events = append(events, GroupChangeEvent{
+			Timestamp: time.UnixMilli(event.Time).UTC(),
+			Actor:     actor,
+			Operation: event.Data.Action,
+			Result:    event.Data.Result,
+		})
+	}
+
+	return events, nil
+}
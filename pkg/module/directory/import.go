@@ -0,0 +1,228 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+)
+
+// ImportGroupsResult describes the outcome of ImportGroups for each group, keyed by
+// display name.
+type ImportGroupsResult struct {
+	// Created maps each created group to its URL.
+	Created map[string]string
+	// Updated maps each existing group that was updated to its URL.
+	Updated map[string]string
+	// Skipped lists the existing groups that were left unchanged.
+	Skipped []string
+	// MissingUsers maps a group to the member and owner usernames that were left
+	// out because they do not exist in the tenant.
+	MissingUsers map[string][]string
+	Failed       map[string]error
+}
+
+// Err returns the failures as a single error, or nil if there are none.
+func (r *ImportGroupsResult) Err() error {
+	return joinFailures(r.Failed)
+}
+
+// ImportGroups creates the groups in a document written by ExportGroups, resolving
+// the member and owner usernames in this tenant. Groups that already exist are
+// updated to match the document if update is set, and skipped otherwise. Usernames
+// that do not exist in the tenant are left out with a warning, rather than failing
+// the group. Groups are imported after the groups they contain, so nested groups
+// defined in the same document resolve. A failure does not stop the remaining
+// groups from being imported; an error is only returned if the document cannot be
+// read.
+func (c *GroupClient) ImportGroups(ctx context.Context, auth *config.AuthConfig, reader io.Reader, format string, update bool) (*ImportGroupsResult, error) {
+	vc := config.GetVerifyContext(ctx)
+	groups, err := readGroupExport(reader, format)
+	if err != nil {
+		vc.Logger.Errorf("unable to read the groups; err=%s", err.Error())
+		return nil, err
+	}
+
+	result := &ImportGroupsResult{
+		Created:      map[string]string{},
+		Updated:      map[string]string{},
+		Skipped:      []string{},
+		MissingUsers: map[string][]string{},
+		Failed:       map[string]error{},
+	}
+
+	usernames := []string{}
+	for _, group := range groups {
+		usernames = append(usernames, groupUsernames(group)...)
+	}
+
+	// the IDs are cached by the user client, so only the missing users matter here
+	userIDs, err := c.users.BatchResolveUserIDs(ctx, auth, usernames)
+	if err != nil && userIDs == nil {
+		vc.Logger.Errorf("unable to get the user IDs; err=%s", err.Error())
+		return nil, fmt.Errorf("unable to get the user IDs; err=%w", err)
+	}
+
+	for _, group := range orderByNesting(groups) {
+		if ctx.Err() != nil {
+			result.Failed[group.DisplayName] = ctx.Err()
+			continue
+		}
+
+		if missing := omitMissingUsers(group, userIDs); len(missing) > 0 {
+			vc.Logger.Warnf("the users %v do not exist and are left out of the group %s", missing, group.DisplayName)
+			result.MissingUsers[group.DisplayName] = missing
+		}
+
+		if update {
+			applied, err := c.ApplyGroup(ctx, auth, group)
+			if err != nil {
+				result.Failed[group.DisplayName] = err
+			} else if applied.Created {
+				result.Created[group.DisplayName] = applied.URI
+			} else {
+				result.Updated[group.DisplayName] = applied.URI
+			}
+			continue
+		}
+
+		// CreateGroup resolves the members but not the owners
+		if err := c.resolveOwners(ctx, auth, group.IBMGROUP.Owners); err != nil {
+			result.Failed[group.DisplayName] = err
+			continue
+		}
+
+		uri, created, err := c.CreateGroupIfNotExists(ctx, auth, group)
+		if err != nil {
+			result.Failed[group.DisplayName] = err
+		} else if created {
+			result.Created[group.DisplayName] = uri
+		} else {
+			result.Skipped = append(result.Skipped, group.DisplayName)
+		}
+	}
+
+	return result, nil
+}
+
+// readGroupExport parses the groups in a document written by ExportGroups.
+func readGroupExport(reader io.Reader, format string) ([]*Group, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the groups; err=%w", err)
+	}
+
+	b, _, err := documentJSON(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := struct {
+		Items []json.RawMessage `json:"items"`
+	}{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("invalid group export; err=%w", err)
+	}
+
+	groups := []*Group{}
+	for i, item := range doc.Items {
+		group, err := parseGroup(item, "json", &loadOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("invalid group at index %d; %w", i, err)
+		}
+
+		groups = append(groups, group)
+	}
+
+	if len(groups) == 0 {
+		return nil, module.MakeSimpleError("the document has no groups")
+	}
+
+	return groups, nil
+}
+
+// groupUsernames returns the usernames of the user members and owners of the group.
+func groupUsernames(group *Group) []string {
+	usernames := []string{}
+	for _, m := range group.Members {
+		if !isGroupMember(m.Type) {
+			usernames = append(usernames, m.Value)
+		}
+	}
+
+	for _, owner := range group.IBMGROUP.Owners {
+		usernames = append(usernames, owner.Value)
+	}
+
+	return usernames
+}
+
+// omitMissingUsers removes the user members and owners that are not in userIDs from
+// the group and returns their usernames.
+func omitMissingUsers(group *Group, userIDs map[string]string) []string {
+	missing := []string{}
+	group.Members = slices.DeleteFunc(group.Members, func(m Member) bool {
+		if _, ok := userIDs[m.Value]; ok || isGroupMember(m.Type) {
+			return false
+		}
+
+		missing = append(missing, m.Value)
+		return true
+	})
+
+	group.IBMGROUP.Owners = slices.DeleteFunc(group.IBMGROUP.Owners, func(owner Owner) bool {
+		if _, ok := userIDs[owner.Value]; ok {
+			return false
+		}
+
+		missing = append(missing, owner.Value)
+		return true
+	})
+
+	return missing
+}
+
+// orderByNesting orders the groups so that each group comes after the groups in
+// the list that it contains. Groups in a cycle keep their original order.
+func orderByNesting(groups []*Group) []*Group {
+	pending := slices.Clone(groups)
+	ordered := []*Group{}
+	done := map[string]bool{}
+	for len(pending) > 0 {
+		inList := map[string]bool{}
+		for _, group := range pending {
+			inList[group.DisplayName] = true
+		}
+
+		next := []*Group{}
+		for _, group := range pending {
+			ready := true
+			for _, m := range group.Members {
+				if isGroupMember(m.Type) && inList[m.Value] && !done[m.Value] && m.Value != group.DisplayName {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				ordered = append(ordered, group)
+				done[group.DisplayName] = true
+			} else {
+				next = append(next, group)
+			}
+		}
+
+		// a cycle; the remaining groups are imported as they are
+		if len(next) == len(pending) {
+			return append(ordered, next...)
+		}
+
+		pending = next
+	}
+
+	return ordered
+}
@@ -0,0 +1,59 @@
+package directory
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+)
+
+func TestImportGroupsResolvesOwners(t *testing.T) {
+	document := `{"items":[{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group", "urn:ietf:params:scim:schemas:extension:ibm:2.0:Group"],
+		"displayName": "Sales",
+		"members": [{"type": "User", "value": "jdoe"}],
+		"urn:ietf:params:scim:schemas:extension:ibm:2.0:Group": {"owners": [{"value": "asmith"}]}
+	}]}`
+
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, `{"totalResults":2,"Resources":[{"id":"u1","userName":"jdoe"},{"id":"u2","userName":"asmith"}]}`).
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":0}`).
+		On(http.MethodPost, "/v2.0/Groups", http.StatusCreated, `{"id":"g1"}`)
+	client := NewGroupClientWithClient(fake)
+
+	result, err := client.ImportGroups(testContext(t), testAuth, strings.NewReader(document), "json", false)
+	if err != nil {
+		t.Fatalf("ImportGroups() err = %v, want nil", err)
+	}
+
+	if err := result.Err(); err != nil || len(result.Created) != 1 {
+		t.Fatalf("ImportGroups() created %v, err = %v, want Sales created", result.Created, err)
+	}
+
+	var post *directorytest.Request
+	for _, r := range fake.Requests() {
+		if r.Method == http.MethodPost {
+			post = &r
+		}
+	}
+
+	if post == nil {
+		t.Fatal("the group was not posted")
+	}
+
+	posted := &Group{}
+	if err := json.Unmarshal(post.Body, posted); err != nil {
+		t.Fatalf("unable to decode the posted group; err=%v", err)
+	}
+
+	if want := []Member{{Type: "User", Value: "u1"}}; !reflect.DeepEqual(posted.Members, want) {
+		t.Errorf("posted members = %+v, want %+v", posted.Members, want)
+	}
+
+	if want := []Owner{{Value: "u2"}}; !reflect.DeepEqual(posted.IBMGROUP.Owners, want) {
+		t.Errorf("posted owners = %+v, want %+v", posted.IBMGROUP.Owners, want)
+	}
+}
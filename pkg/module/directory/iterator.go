@@ -0,0 +1,89 @@
+package directory
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+)
+
+// GroupIterator yields the groups matching a filter one at a time, fetching each
+// page of results only when it is needed.
+type GroupIterator struct {
+	client *GroupClient
+	auth   *config.AuthConfig
+	filter string
+	sort   string
+
+	page       []Group
+	startIndex int
+	returned   int
+	total      int
+	done       bool
+	err        error
+}
+
+// IterateGroups returns an iterator over the groups matching the filter, ordered by
+// sort. Empty values are ignored.
+func (c *GroupClient) IterateGroups(auth *config.AuthConfig, filter string, sort string) *GroupIterator {
+	return &GroupIterator{
+		client:     c,
+		auth:       auth,
+		filter:     filter,
+		sort:       sort,
+		startIndex: 1,
+	}
+}
+
+// Next returns the next group. It returns io.EOF once every group has been returned.
+// If fetching a page fails, the error is returned by this and every later call.
+func (it *GroupIterator) Next(ctx context.Context) (*Group, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(it.page) == 0 {
+		if it.done {
+			return nil, io.EOF
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return nil, err
+		}
+
+		if len(it.page) == 0 {
+			return nil, io.EOF
+		}
+	}
+
+	group := it.page[0]
+	it.page = it.page[1:]
+	it.returned++
+	return &group, nil
+}
+
+func (it *GroupIterator) fetch(ctx context.Context) error {
+	page, _, err := it.client.GetGroups(ctx, it.auth, it.filter, it.sort, "", strconv.Itoa(defaultGroupPageSize), strconv.Itoa(it.startIndex))
+	if err != nil {
+		return err
+	}
+
+	it.page = page.Groups
+	it.total = page.TotalResults
+
+	// itemsPerPage is optional, so the number of resources returned is used to
+	// advance the index. A total that is not positive is treated as unknown, in
+	// which case paging stops at the first empty page.
+	it.startIndex += len(page.Groups)
+	if len(page.Groups) == 0 || (it.total > 0 && it.returned+len(page.Groups) >= it.total) {
+		it.done = true
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+// cancellingClient cancels the context once the first GET has been answered, as
+// a user pressing Ctrl-C during a long list would.
+type cancellingClient struct {
+	*directorytest.FakeClient
+	cancel context.CancelFunc
+}
+
+func (c *cancellingClient) Get(ctx context.Context, u *url.URL, headers http.Header) (*xhttp.Response, error) {
+	response, err := c.FakeClient.Get(ctx, u, headers)
+	c.cancel()
+	return response, err
+}
+
+func TestGetAllGroupsCancelledAfterFirstPage(t *testing.T) {
+	ctx, cancel := context.WithCancel(testContext(t))
+	defer cancel()
+
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":2,"Resources":[{"id":"g1","displayName":"Sales"}]}`).
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":2,"Resources":[{"id":"g2","displayName":"Admins"}]}`)
+	client := NewGroupClientWithClient(&cancellingClient{FakeClient: fake, cancel: cancel})
+
+	groups, err := client.GetAllGroups(ctx, testAuth, "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetAllGroups() err = %v, want %v", err, context.Canceled)
+	}
+
+	if groups != nil {
+		t.Errorf("GetAllGroups() = %d groups, want none with the error", len(groups))
+	}
+
+	if n := len(fake.Requests()); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+}
+
+func TestGetAllGroupsFollowsPages(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":2,"Resources":[{"id":"g1","displayName":"Sales"}]}`).
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":2,"Resources":[{"id":"g2","displayName":"Admins"}]}`)
+	client := NewGroupClientWithClient(fake)
+
+	groups, err := client.GetAllGroups(testContext(t), testAuth, "")
+	if err != nil {
+		t.Fatalf("GetAllGroups() err = %v, want nil", err)
+	}
+
+	if len(groups) != 2 {
+		t.Fatalf("GetAllGroups() = %d groups, want 2", len(groups))
+	}
+
+	if got := fake.Requests()[1].URL.Query().Get("startIndex"); got != "2" {
+		t.Errorf("second page startIndex = %s, want 2", got)
+	}
+}
+
+func TestGetAllGroupsWithoutTotal(t *testing.T) {
+	for name, total := range map[string]string{"missing": "", "zero": `"totalResults":0,`} {
+		t.Run(name, func(t *testing.T) {
+			fake := directorytest.NewFakeClient().
+				On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{`+total+`"Resources":[{"id":"g1","displayName":"Sales"}]}`).
+				On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{`+total+`"Resources":[{"id":"g2","displayName":"Admins"}]}`).
+				On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{`+total+`"Resources":[]}`)
+			client := NewGroupClientWithClient(fake)
+
+			groups, err := client.GetAllGroups(testContext(t), testAuth, "")
+			if err != nil {
+				t.Fatalf("GetAllGroups() err = %v, want nil", err)
+			}
+
+			if len(groups) != 2 {
+				t.Errorf("GetAllGroups() = %d groups, want 2", len(groups))
+			}
+
+			if n := len(fake.Requests()); n != 3 {
+				t.Errorf("sent %d requests, want 3", n)
+			}
+		})
+	}
+}
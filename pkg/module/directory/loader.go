@@ -0,0 +1,160 @@
+package directory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadOption configures how a group definition is parsed.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	strict bool
+}
+
+// WithStrictFields rejects attributes that the group does not define, such as a
+// misspelled key, instead of keeping them as additional properties. Schema
+// extensions without a field of their own are still allowed.
+func WithStrictFields() LoadOption {
+	return func(o *loadOptions) {
+		o.strict = true
+	}
+}
+
+func newLoadOptions(opts []LoadOption) *loadOptions {
+	o := &loadOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// LoadGroupFromFile reads a group definition from a JSON or YAML file. The format
+// is taken from the file extension, or detected from the content if the extension
+// is not .json, .yaml or .yml. See ParseGroup for the accepted content.
+func LoadGroupFromFile(path string, opts ...LoadOption) (*Group, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the group file %s; err=%w", path, err)
+	}
+
+	format := ""
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = "json"
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+
+	group, err := parseGroup(b, format, newLoadOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("invalid group file %s; %w", path, err)
+	}
+
+	return group, nil
+}
+
+// ParseGroup parses a group definition in JSON or YAML. The definition is either the
+// group itself or a resource object, as written by 'verifyctl get group', with the
+// group under "data". The group must pass Validate.
+func ParseGroup(data []byte, opts ...LoadOption) (*Group, error) {
+	return parseGroup(data, "", newLoadOptions(opts))
+}
+
+func parseGroup(data []byte, format string, options *loadOptions) (*Group, error) {
+	b, format, err := documentJSON(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	// unwrap a resource object
+	wrapper := struct {
+		Kind string          `json:"kind"`
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(b, &wrapper); err == nil && len(wrapper.Kind) > 0 && len(wrapper.Data) > 0 {
+		b = wrapper.Data
+	}
+
+	if options.strict {
+		if err := checkGroupFields(b); err != nil {
+			return nil, err
+		}
+	}
+
+	group := &Group{}
+	if err := json.Unmarshal(b, group); err != nil {
+		if format == "json" && bytes.Equal(b, data) {
+			return nil, jsonErrorWithLine(data, err)
+		}
+
+		return nil, fmt.Errorf("invalid group; err=%w", err)
+	}
+
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// documentJSON returns the JSON or YAML document as JSON, along with its format,
+// which is detected from the content if it is empty. YAML is converted to JSON so
+// that unknown attributes are kept by the JSON decoding of the group.
+func documentJSON(data []byte, format string) ([]byte, string, error) {
+	if len(format) == 0 {
+		format = "yaml"
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			format = "json"
+		}
+	}
+
+	if format == "json" {
+		if err := json.Unmarshal(data, &map[string]interface{}{}); err != nil {
+			return nil, format, jsonErrorWithLine(data, err)
+		}
+
+		return data, format, nil
+	}
+
+	m := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, format, fmt.Errorf("malformed YAML; err=%w", err)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, format, fmt.Errorf("malformed YAML; err=%w", err)
+	}
+
+	return b, format, nil
+}
+
+// jsonErrorWithLine adds the line and column where decoding failed to the error.
+func jsonErrorWithLine(data []byte, err error) error {
+	offset := int64(-1)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+	} else if errors.As(err, &typeErr) {
+		offset = typeErr.Offset
+	}
+
+	if offset < 0 || offset > int64(len(data)) {
+		return fmt.Errorf("malformed JSON; err=%w", err)
+	}
+
+	prefix := data[:offset]
+	line := bytes.Count(prefix, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(prefix, '\n')
+	return fmt.Errorf("malformed JSON at line %d, column %d; err=%w", line, column, err)
+}
@@ -0,0 +1,189 @@
+package directory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+)
+
+// PatchBuilder accumulates SCIM PATCH operations.
+//
+//	operations, err := directory.NewPatchBuilder().
+//		Replace("description", "Reviewed").
+//		Remove(`members[value eq "1234"]`).
+//		Operations()
+type PatchBuilder struct {
+	operations []GroupSCIMOpEntry
+	err        error
+}
+
+// NewPatchBuilder returns an empty PatchBuilder.
+func NewPatchBuilder() *PatchBuilder {
+	return &PatchBuilder{
+		operations: []GroupSCIMOpEntry{},
+	}
+}
+
+// Add appends an "add" operation. If path is empty, value must be an object of attributes.
+func (b *PatchBuilder) Add(path string, value interface{}) *PatchBuilder {
+	return b.append("add", path, value)
+}
+
+// Replace appends a "replace" operation. If path is empty, value must be an object of attributes.
+func (b *PatchBuilder) Replace(path string, value interface{}) *PatchBuilder {
+	return b.append("replace", path, value)
+}
+
+// Remove appends a "remove" operation.
+func (b *PatchBuilder) Remove(path string) *PatchBuilder {
+	return b.append("remove", path, nil)
+}
+
+// Operations validates and returns the accumulated operations.
+func (b *PatchBuilder) Operations() ([]GroupSCIMOpEntry, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if err := validateGroupOperations(b.operations); err != nil {
+		return nil, err
+	}
+
+	return b.operations, nil
+}
+
+// Request validates the accumulated operations and returns them as a patch request.
+func (b *PatchBuilder) Request() (*GroupSCIMPatchRequest, error) {
+	operations, err := b.Operations()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GroupSCIMPatchRequest{
+		Schemas:    []string{patchRequestSchema},
+		Operations: operations,
+	}, nil
+}
+
+func (b *PatchBuilder) append(op string, path string, value interface{}) *PatchBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	// values are normalized to their JSON form so that typed slices and structs are
+	// validated the same way as decoded request bodies.
+	if value != nil {
+		v, err := normalizeValue(value)
+		if err != nil {
+			b.err = module.MakeSimpleError(fmt.Sprintf("operation %d (%s) has a value that cannot be encoded; err=%v", len(b.operations), op, err))
+			return b
+		}
+
+		value = v
+	}
+
+	b.operations = append(b.operations, GroupSCIMOpEntry{
+		Op:    op,
+		Path:  path,
+		Value: value,
+	})
+
+	return b
+}
+
+func normalizeValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// operationIndexPattern matches the index of the failing operation in the detail of
+// a SCIM error, such as "Operations[2]" or "operation 2".
+var operationIndexPattern = regexp.MustCompile(`(?i)operations?\s*(?:\[\s*(\d+)\s*\]|#?\s*(\d+))`)
+
+// PatchError is returned when a PATCH request fails. Verify applies the operations
+// of a request atomically, as RFC 7644 section 3.5.2 requires, so if the tenant
+// rejected the request with a SCIM error none of them have been applied. If the
+// request failed otherwise, such as with a server error, the state of the group is
+// unknown.
+type PatchError struct {
+	// Operations are the operations sent in the request, with usernames resolved.
+	Operations []GroupSCIMOpEntry
+
+	// FailedIndex is the index of the operation the tenant reported as failing, or
+	// -1 if the error does not identify one.
+	FailedIndex int
+
+	Err error
+}
+
+func newPatchError(operations []GroupSCIMOpEntry, err error) *PatchError {
+	patchErr := &PatchError{
+		Operations:  operations,
+		FailedIndex: -1,
+		Err:         err,
+	}
+
+	var scimErr *module.SCIMError
+	if !errors.As(err, &scimErr) {
+		return patchErr
+	}
+
+	match := operationIndexPattern.FindStringSubmatch(scimErr.Detail)
+	if match == nil {
+		return patchErr
+	}
+
+	index, convErr := strconv.Atoi(match[1] + match[2])
+	if convErr == nil && index >= 0 && index < len(operations) {
+		patchErr.FailedIndex = index
+	}
+
+	return patchErr
+}
+
+// Failed returns the operation the tenant reported as failing.
+func (e *PatchError) Failed() (GroupSCIMOpEntry, bool) {
+	if e.FailedIndex < 0 {
+		return GroupSCIMOpEntry{}, false
+	}
+
+	return e.Operations[e.FailedIndex], true
+}
+
+// rejected returns true if the tenant rejected the request with a SCIM error, in
+// which case none of the operations were applied.
+func (e *PatchError) rejected() bool {
+	var scimErr *module.SCIMError
+	status := module.StatusCode(e.Err)
+	return status >= 400 && status < 500 && errors.As(e.Err, &scimErr)
+}
+
+func (e *PatchError) Error() string {
+	outcome := "it is unknown whether the operations were applied"
+	if e.rejected() {
+		outcome = "no operations were applied"
+	}
+
+	if op, ok := e.Failed(); ok {
+		return fmt.Sprintf("operation %d (%s %s) failed and %s; err=%s", e.FailedIndex, op.Op, op.Path, outcome, e.Err.Error())
+	}
+
+	return fmt.Sprintf("%s; err=%s", outcome, e.Err.Error())
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,60 @@
+package directory
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+)
+
+func TestPatchErrorOutcome(t *testing.T) {
+	scimError := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"%s","scimType":"invalidValue","detail":"Operations[1] has an invalid value"}`
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{
+			name:   "rejected",
+			status: http.StatusBadRequest,
+			body:   fmt.Sprintf(scimError, "400"),
+			want:   "no operations were applied",
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			body:   fmt.Sprintf(scimError, "500"),
+			want:   "unknown",
+		},
+		{
+			name:   "gateway error",
+			status: http.StatusBadGateway,
+			body:   "<html>Bad Gateway</html>",
+			want:   "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := onGroupLookup(directorytest.NewFakeClient(), "g1").
+				On(http.MethodPatch, "/v2.0/Groups/g1", tt.status, tt.body)
+			client := NewGroupClientWithClient(fake)
+
+			err := client.UpdateGroupDescription(testContext(t), testAuth, "Sales", "Sales team")
+
+			var patchErr *PatchError
+			if !errors.As(err, &patchErr) {
+				t.Fatalf("UpdateGroupDescription() err = %v, want a *PatchError", err)
+			}
+
+			if !strings.Contains(patchErr.Error(), tt.want) {
+				t.Errorf("Error() = %q, want it to say %q", patchErr.Error(), tt.want)
+			}
+		})
+	}
+}
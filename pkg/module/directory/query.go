@@ -0,0 +1,35 @@
+package directory
+
+import (
+	"net/url"
+	"strings"
+)
+
+// QueryOption adds optional SCIM query parameters to a read request.
+type QueryOption func(q url.Values)
+
+// WithAttributes limits the response to the attributes, for example "displayName"
+// or "members.value". The id attribute is always returned.
+func WithAttributes(attributes ...string) QueryOption {
+	return func(q url.Values) {
+		if len(attributes) > 0 {
+			q.Set("attributes", strings.Join(attributes, ","))
+		}
+	}
+}
+
+// WithExcludedAttributes removes the attributes, for example "members", from the
+// response.
+func WithExcludedAttributes(attributes ...string) QueryOption {
+	return func(q url.Values) {
+		if len(attributes) > 0 {
+			q.Set("excludedAttributes", strings.Join(attributes, ","))
+		}
+	}
+}
+
+func applyQueryOptions(q url.Values, opts []QueryOption) {
+	for _, opt := range opts {
+		opt(q)
+	}
+}
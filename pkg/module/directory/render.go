@@ -0,0 +1,215 @@
+package directory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputFormatJSON  = "json"
+	OutputFormatYAML  = "yaml"
+	OutputFormatTable = "table"
+	OutputFormatWide  = "wide"
+
+	// maxColumnWidth is the width at which table cells are truncated.
+	maxColumnWidth = 40
+)
+
+// FormatGroup renders the group in the output format, which is either "json" or "yaml".
+func FormatGroup(group *Group, format string) ([]byte, error) {
+	return formatResource(group, format)
+}
+
+// FormatGroupList renders the list of groups in the output format, which is one of
+// "json", "yaml", "table" or "wide".
+func FormatGroupList(groups *GroupListResponse, format string) ([]byte, error) {
+	if format == OutputFormatTable || format == OutputFormatWide {
+		buf := &bytes.Buffer{}
+		if err := WriteGroupTable(buf, groups, format == OutputFormatWide); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	return formatResource(groups, format)
+}
+
+// WriteGroupTable writes the groups as aligned columns. The wide variant adds the
+// description from the IBM group extension. If the groups are a page of a larger
+// result, a footer such as "Showing 1-100 of 4211" follows the table.
+func WriteGroupTable(w io.Writer, groups *GroupListResponse, wide bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	header := "DISPLAYNAME\tID\tMEMBERS\tVISIBLE\tLAST MODIFIED"
+	if wide {
+		header += "\tDESCRIPTION"
+	}
+
+	if _, err := fmt.Fprintln(tw, header); err != nil {
+		return err
+	}
+
+	for _, group := range groups.Groups {
+		row := truncate(group.DisplayName) + "\t" +
+			group.Id + "\t" +
+			strconv.Itoa(group.MemberCount) + "\t" +
+			strconv.FormatBool(group.Visible) + "\t" +
+			group.Meta.LastModified
+		if wide {
+			row += "\t" + truncate(group.IBMGROUP.Description)
+		}
+
+		if _, err := fmt.Fprintln(tw, row); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if footer := pageFooter(groups.StartIndex, len(groups.Groups), groups.TotalResults); len(footer) > 0 {
+		if _, err := fmt.Fprintln(w, footer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pageFooter describes the position of a page of count items in the full result,
+// or returns an empty string if the page holds every item. SCIM start indexes
+// begin at 1.
+func pageFooter(startIndex int, count int, total int) string {
+	if total <= count {
+		return ""
+	}
+
+	if count == 0 {
+		return fmt.Sprintf("Showing 0 of %d", total)
+	}
+
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	return fmt.Sprintf("Showing %d-%d of %d", startIndex, startIndex+count-1, total)
+}
+
+// WriteGroupMembersCSV writes the members of the group as CSV, with a header row.
+// See WriteGroupsMembersCSV.
+func WriteGroupMembersCSV(w io.Writer, group *Group) error {
+	return WriteGroupsMembersCSV(w, []Group{*group})
+}
+
+// WriteGroupsMembersCSV writes one CSV row for each member of each group, with the
+// columns USERNAME, DISPLAYNAME, TYPE and GROUP, under a single header row. Values
+// are quoted as described in RFC 4180. The members should be resolved first, such
+// as with GetGroupWithMembers; otherwise the username column holds the member ID.
+func WriteGroupsMembersCSV(w io.Writer, groups []Group) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"USERNAME", "DISPLAYNAME", "TYPE", "GROUP"}); err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		for _, m := range group.Members {
+			username := m.UserName
+			if len(username) == 0 {
+				username = m.Value
+			}
+
+			memberType := m.Type
+			if len(memberType) == 0 {
+				memberType = "User"
+			}
+
+			if err := cw.Write([]string{username, m.Display, memberType, group.DisplayName}); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// truncate shortens the value to the column width and strips characters that would
+// break the table layout.
+func truncate(value string) string {
+	runes := []rune(value)
+	for i, r := range runes {
+		if r == '\t' || r == '\n' || r == '\r' {
+			runes[i] = ' '
+		}
+	}
+
+	if len(runes) > maxColumnWidth {
+		return string(runes[:maxColumnWidth-3]) + "..."
+	}
+
+	return string(runes)
+}
+
+// formatResource renders the resource through its JSON representation so that both
+// formats carry the same fields, including the URN keyed extensions. Empty objects,
+// such as an unset extension, are omitted from both formats.
+func formatResource(obj interface{}, format string) ([]byte, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	data = pruneEmptyObjects(data)
+
+	switch format {
+	case "", OutputFormatJSON:
+		return json.MarshalIndent(data, "", "  ")
+	case OutputFormatYAML:
+		buf := &bytes.Buffer{}
+		encoder := yaml.NewEncoder(buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(data); err != nil {
+			return nil, err
+		}
+
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %s; expected json or yaml", format)
+	}
+}
+
+func pruneEmptyObjects(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			value = pruneEmptyObjects(value)
+			if m, ok := value.(map[string]interface{}); ok && len(m) == 0 {
+				delete(v, key)
+				continue
+			}
+			v[key] = value
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = pruneEmptyObjects(value)
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,134 @@
+package directory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+)
+
+var (
+	unknownFieldPattern = regexp.MustCompile(`unknown field "([^"]*)"`)
+
+	// knownGroupFields holds the JSON names of the fields of the group and the
+	// types it contains, which misspelled keys are compared with.
+	knownGroupFields = collectFieldNames(reflect.TypeOf(Group{}), map[string]bool{})
+)
+
+// checkGroupFields returns an error naming the first attribute in the group that
+// has no field, along with the closest known field if there is one. Attributes
+// that are schema extensions without a field of their own are skipped.
+func checkGroupFields(b []byte) error {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("invalid group; err=%w", err)
+	}
+
+	for key := range raw {
+		if strings.HasPrefix(key, "urn:") && !groupFields[key] {
+			delete(raw, key)
+		}
+	}
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("invalid group; err=%w", err)
+	}
+
+	// the strictGroup type has none of the methods of Group, so the decoding is
+	// not relaxed by its UnmarshalJSON
+	type strictGroup Group
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&strictGroup{}); err != nil {
+		match := unknownFieldPattern.FindStringSubmatch(err.Error())
+		if match == nil {
+			return fmt.Errorf("invalid group; err=%w", err)
+		}
+
+		message := fmt.Sprintf("invalid group; unknown field %q", match[1])
+		if suggestion := closestField(match[1]); len(suggestion) > 0 {
+			message += fmt.Sprintf("; did you mean %q?", suggestion)
+		}
+
+		return module.MakeSimpleError(message)
+	}
+
+	return nil
+}
+
+// collectFieldNames adds the JSON names of the fields of the struct type and of
+// the struct types it contains to names.
+func collectFieldNames(t reflect.Type, names map[string]bool) map[string]bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+
+	for name := range jsonFieldNames(t) {
+		names[name] = true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			collectFieldNames(t.Field(i).Type, names)
+		}
+	}
+
+	return names
+}
+
+// closestField returns the known field closest to the name, or an empty string if
+// none is close enough to be a likely typo. A name that matches a known field
+// exactly, such as one that is only defined in an extension, has no suggestion.
+func closestField(name string) string {
+	best := ""
+	bestDistance := 0
+	for field := range knownGroupFields {
+		if field == name {
+			return ""
+		}
+
+		d := editDistance(strings.ToLower(name), strings.ToLower(field))
+		if len(best) == 0 || d < bestDistance || (d == bestDistance && field < best) {
+			best = field
+			bestDistance = d
+		}
+	}
+
+	if bestDistance > max(2, len(name)/3) {
+		return ""
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between the strings.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
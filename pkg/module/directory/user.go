@@ -6,22 +6,46 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
 	"github.com/ibm-security-verify/verifyctl/pkg/config"
 	"github.com/ibm-security-verify/verifyctl/pkg/module"
 	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+	typesx "github.com/ibm-security-verify/verifyctl/pkg/util/types"
+	"github.com/ibm-security-verify/verifyctl/x/logx"
 )
 
 const (
-	apiUsers = "v2.0/Users"
+	apiUsers = "Users"
+
+	coreUserSchema                = "urn:ietf:params:scim:schemas:core:2.0:User"
+	ibmUserExtensionSchema        = "urn:ietf:params:scim:schemas:extension:ibm:2.0:User"
+	enterpriseUserExtensionSchema = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+
+	// userFilterChunkSize is the number of usernames combined into a single
+	// SCIM filter, which keeps the request URL within reasonable limits.
+	userFilterChunkSize = 50
 )
 
 type UserClient struct {
+	endpoint
+
 	client xhttp.Clientx
+
+	// cache is swapped by SetCacheEnabled while requests may be running.
+	cache atomic.Pointer[userIDCache]
+
+	// skipPasswordReset is sent as the usershouldnotneedtoresetpassword header.
+	skipPasswordReset bool
 }
 
 type UserListResponse struct {
 	TotalResults int      `json:"totalResults" yaml:"totalResults"`
+	StartIndex   int      `json:"startIndex,omitempty" yaml:"startIndex,omitempty"`
+	ItemsPerPage int      `json:"itemsPerPage,omitempty" yaml:"itemsPerPage,omitempty"`
 	Schemas      []string `json:"schemas" yaml:"schemas"`
 	Users        []User   `json:"Resources" yaml:"Resources"`
 }
@@ -138,20 +162,53 @@ type UserSCIMOpEntry struct {
 }
 
 func NewUserClient() *UserClient {
-	return &UserClient{
-		client: xhttp.NewDefaultClient(),
+	return NewUserClientWithClient(LimitConcurrency(xhttp.NewDefaultClient(), DefaultConcurrencyLimiter))
+}
+
+// NewUserClientWithClient returns a UserClient that makes requests using the provided client.
+func NewUserClientWithClient(client xhttp.Clientx) *UserClient {
+	c := &UserClient{
+		client: client,
+	}
+	c.cache.Store(newUserIDCache(defaultUserIDCacheSize))
+
+	return c
+}
+
+// SetCacheEnabled turns the cache of resolved user IDs on or off. The cache lives as
+// long as the client and is enabled by default. Long running processes, where user
+// accounts may be recreated, should disable it.
+func (c *UserClient) SetCacheEnabled(enabled bool) {
+	if !enabled {
+		c.cache.Store(nil)
+	} else {
+		c.cache.CompareAndSwap(nil, newUserIDCache(defaultUserIDCacheSize))
 	}
 }
 
+// SetSkipPasswordReset sets the usershouldnotneedtoresetpassword header sent when
+// a user is created or updated. It is false by default, which leaves
+// the tenant to decide whether the user must reset the password.
+func (c *UserClient) SetSkipPasswordReset(skip bool) {
+	c.skipPasswordReset = skip
+}
+
+// CreateUser creates the user and returns its URL. The schemas are filled in from
+// the extensions the user carries if none are set.
 func (c *UserClient) CreateUser(ctx context.Context, auth *config.AuthConfig, user *User) (string, error) {
 	vc := config.GetVerifyContext(ctx)
 	defaultErr := fmt.Errorf("unable to create user.")
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s", auth.Tenant, apiUsers))
-	headers := http.Header{
-		"Accept":                           []string{"application/scim+json"},
-		"Content-Type":                     []string{"application/scim+json"},
-		"usershouldnotneedtoresetpassword": []string{"false"},
-		"Authorization":                    []string{"Bearer " + auth.Token},
+	u, _ := url.Parse(c.resourceURL(auth, apiUsers))
+	headers := scimBodyHeaders(auth)
+	headers.Set("usershouldnotneedtoresetpassword", strconv.FormatBool(c.skipPasswordReset))
+
+	if len(user.Schemas) == 0 {
+		user.Schemas = defaultUserSchemas(user)
+	}
+
+	if err := user.Validate(); err != nil {
+		vc.Logger.Errorf("unable to create the user; err=%s", err.Error())
+		return "", err
 	}
 
 	b, err := json.Marshal(user)
@@ -167,6 +224,11 @@ func (c *UserClient) CreateUser(ctx context.Context, auth *config.AuthConfig, us
 		return "", defaultErr
 	}
 
+	if response.StatusCode == http.StatusConflict {
+		vc.Logger.Errorf("unable to create the user; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return "", module.NewAPIError(response, fmt.Errorf("%w with username %s", ErrUserExists, user.UserName))
+	}
+
 	if response.StatusCode != http.StatusCreated {
 		if err := module.HandleCommonErrors(ctx, response, "unable to create user"); err != nil {
 			vc.Logger.Errorf("unable to create the user; err=%s", err.Error())
@@ -174,7 +236,7 @@ func (c *UserClient) CreateUser(ctx context.Context, auth *config.AuthConfig, us
 		}
 
 		vc.Logger.Errorf("unable to create the user; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return "", fmt.Errorf("unable to create the user; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return "", module.NewAPIError(response, fmt.Errorf("unable to create the user; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body))))
 	}
 
 	m := map[string]interface{}{}
@@ -182,22 +244,73 @@ func (c *UserClient) CreateUser(ctx context.Context, auth *config.AuthConfig, us
 		return "", fmt.Errorf("Failed to parse response")
 	}
 
-	id := m["id"].(string)
-	return fmt.Sprintf("https://%s/%s/%s", auth.Tenant, apiUsers, id), nil
+	id := typesx.Map(m).SafeString("id", "")
+	if len(id) == 0 {
+		if location := response.Headers.Get("Location"); len(location) > 0 {
+			return location, nil
+		}
+
+		vc.Logger.Errorf("User created but the response has no valid 'id'; body=%s", string(response.Body))
+		return "", fmt.Errorf("user created but the response does not contain a valid 'id'")
+	}
+
+	return c.resourceURL(auth, apiUsers, id), nil
+}
+
+// Validate checks that the user has the attributes required to create it and
+// reports every problem found.
+func (u *User) Validate() error {
+	problems := []string{}
+	if len(strings.TrimSpace(u.UserName)) == 0 {
+		problems = append(problems, "userName is required")
+	}
+
+	if len(u.Schemas) == 0 {
+		problems = append(problems, "schemas is required")
+	}
+
+	for i, email := range u.Emails {
+		if len(email.Value) == 0 {
+			problems = append(problems, fmt.Sprintf("email %d has no value", i))
+		}
+	}
+
+	if len(problems) > 0 {
+		return module.MakeSimpleError("invalid user; " + strings.Join(problems, "; "))
+	}
+
+	return nil
 }
 
+// defaultUserSchemas returns the schemas of the user based on the extensions it uses.
+func defaultUserSchemas(user *User) []string {
+	schemas := []string{coreUserSchema}
+	if !reflect.DeepEqual(user.IBMUserExtension, IBMUser{}) {
+		schemas = append(schemas, ibmUserExtensionSchema)
+	}
+
+	if user.EnterpriseUser != nil {
+		schemas = append(schemas, enterpriseUserExtensionSchema)
+	}
+
+	if user.Notification != (Notification{}) {
+		schemas = append(schemas, ibmNotificationExtensionSchema)
+	}
+
+	return schemas
+}
+
+// GetUser returns the user with the username. ErrUserNotFound is returned if there
+// is no such user.
 func (c *UserClient) GetUser(ctx context.Context, auth *config.AuthConfig, userName string) (*User, string, error) {
 	vc := config.GetVerifyContext(ctx)
 	id, err := c.getUserId(ctx, auth, userName)
 	if err != nil {
-		vc.Logger.Errorf("unable to get the group ID; err=%s", err.Error())
+		vc.Logger.Errorf("unable to get the user ID; err=%s", err.Error())
 		return nil, "", err
 	}
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s/%s", auth.Tenant, apiUsers, id))
-	headers := http.Header{
-		"Accept":        []string{"application/scim+json"},
-		"Authorization": []string{"Bearer " + auth.Token},
-	}
+	u, _ := url.Parse(c.resourceURL(auth, apiUsers, id))
+	headers := scimHeaders(auth)
 
 	response, err := c.client.Get(ctx, u, headers)
 	if err != nil {
@@ -205,6 +318,13 @@ func (c *UserClient) GetUser(ctx context.Context, auth *config.AuthConfig, userN
 		return nil, "", err
 	}
 
+	// the user may have been deleted since the lookup
+	if response.StatusCode == http.StatusNotFound {
+		c.forgetUserID(auth, userName)
+		vc.Logger.Errorf("unable to get the User; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return nil, "", module.NewAPIError(response, fmt.Errorf("%w with userName %s", ErrUserNotFound, userName))
+	}
+
 	if response.StatusCode != http.StatusOK {
 		if err := module.HandleCommonErrors(ctx, response, "unable to get User"); err != nil {
 			vc.Logger.Errorf("unable to get the User; err=%s", err.Error())
@@ -212,7 +332,7 @@ func (c *UserClient) GetUser(ctx context.Context, auth *config.AuthConfig, userN
 		}
 
 		vc.Logger.Errorf("unable to get the User; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return nil, "", fmt.Errorf("unable to get the User")
+		return nil, "", module.NewAPIError(response, fmt.Errorf("unable to get the User"))
 	}
 
 	User := &User{}
@@ -223,18 +343,22 @@ func (c *UserClient) GetUser(ctx context.Context, auth *config.AuthConfig, userN
 	return User, u.String(), nil
 }
 
-func (c *UserClient) GetUsers(ctx context.Context, auth *config.AuthConfig, sort string, count string) (
+// GetUsers returns a page of users. filter is passed through as the SCIM filter
+// expression and startIndex is the 1-based SCIM index of the first result. Empty
+// values are ignored.
+func (c *UserClient) GetUsers(ctx context.Context, auth *config.AuthConfig, filter string, sort string, count string, startIndex string) (
 	*UserListResponse, string, error) {
 
 	vc := config.GetVerifyContext(ctx)
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s", auth.Tenant, apiUsers))
-	headers := http.Header{
-		"Accept":        []string{"application/scim+json"},
-		"Authorization": []string{"Bearer " + auth.Token},
-	}
+	u, _ := url.Parse(c.resourceURL(auth, apiUsers))
+	headers := scimHeaders(auth)
 
 	q := u.Query()
 
+	if len(filter) > 0 {
+		q.Set("filter", filter)
+	}
+
 	if len(sort) > 0 {
 		q.Set("sortBy", sort)
 	}
@@ -243,6 +367,13 @@ func (c *UserClient) GetUsers(ctx context.Context, auth *config.AuthConfig, sort
 		q.Set("count", count)
 	}
 
+	if len(startIndex) > 0 {
+		if i, err := strconv.Atoi(startIndex); err != nil || i < 1 {
+			return nil, "", fmt.Errorf("invalid startIndex %s; must be an integer greater than or equal to 1", startIndex)
+		}
+		q.Set("startIndex", startIndex)
+	}
+
 	if len(q) > 0 {
 		u.RawQuery = q.Encode()
 	}
@@ -261,7 +392,7 @@ func (c *UserClient) GetUsers(ctx context.Context, auth *config.AuthConfig, sort
 		}
 
 		vc.Logger.Errorf("unable to get the Users; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return nil, "", fmt.Errorf("unable to get the Users")
+		return nil, "", module.NewAPIError(response, fmt.Errorf("unable to get the Users"))
 	}
 
 	UsersResponse := &UserListResponse{}
@@ -273,20 +404,19 @@ func (c *UserClient) GetUsers(ctx context.Context, auth *config.AuthConfig, sort
 	return UsersResponse, u.String(), nil
 }
 
+// DeleteUser deletes the user with the username. ErrUserNotFound is returned if
+// there is no such user.
 func (c *UserClient) DeleteUser(ctx context.Context, auth *config.AuthConfig, name string) error {
 	vc := config.GetVerifyContext(ctx)
 
 	id, err := c.getUserId(ctx, auth, name)
 	if err != nil {
 		vc.Logger.Errorf("unable to get the user ID; err=%s", err.Error())
-		return fmt.Errorf("unable to get the user ID; err=%s", err.Error())
+		return fmt.Errorf("unable to get the user ID; err=%w", err)
 	}
 
-	headers := http.Header{
-		"Content-Type":  []string{"application/json"},
-		"Authorization": []string{"Bearer " + auth.Token},
-	}
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s/%s", auth.Tenant, apiUsers, id))
+	headers := scimHeaders(auth)
+	u, _ := url.Parse(c.resourceURL(auth, apiUsers, id))
 
 	response, err := c.client.Delete(ctx, u, headers)
 	if err != nil {
@@ -294,6 +424,14 @@ func (c *UserClient) DeleteUser(ctx context.Context, auth *config.AuthConfig, na
 		return fmt.Errorf("unable to delete the User; err=%s", err.Error())
 	}
 
+	// a deleted user must not be resolved from the cache again
+	c.forgetUserID(auth, name)
+
+	if response.StatusCode == http.StatusNotFound {
+		vc.Logger.Errorf("unable to delete the User; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return module.NewAPIError(response, fmt.Errorf("unable to delete the User; %w with userName %s", ErrUserNotFound, name))
+	}
+
 	if response.StatusCode != http.StatusNoContent {
 		if err := module.HandleCommonErrors(ctx, response, "unable to delete User"); err != nil {
 			vc.Logger.Errorf("unable to delete the User; err=%s", err.Error())
@@ -301,31 +439,33 @@ func (c *UserClient) DeleteUser(ctx context.Context, auth *config.AuthConfig, na
 		}
 
 		vc.Logger.Errorf("unable to delete the User; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return fmt.Errorf("unable to delete the User; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return module.NewAPIError(response, fmt.Errorf("unable to delete the User; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body))))
 	}
 
 	return nil
 }
 
+// UpdateUser applies the SCIM PATCH operations to the user with the username.
+// ErrUserNotFound is returned if there is no such user and ErrUserConflict if the
+// change clashes with another user, such as a username that is already taken.
 func (c *UserClient) UpdateUser(ctx context.Context, auth *config.AuthConfig, userName string, operations []UserSCIMOpEntry) error {
 	vc := config.GetVerifyContext(ctx)
+	if err := validateUserOperations(operations); err != nil {
+		return err
+	}
 
 	id, err := c.getUserId(ctx, auth, userName)
 	if err != nil {
 		vc.Logger.Errorf("unable to get the user ID; err=%s", err.Error())
-		return fmt.Errorf("unable to get the user ID; err=%s", err.Error())
+		return fmt.Errorf("unable to get the user ID; err=%w", err)
 	}
 
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s/%s", auth.Tenant, apiUsers, id))
-	headers := http.Header{
-		"Accept":                           []string{"application/scim+json"},
-		"Content-Type":                     []string{"application/scim+json"},
-		"usershouldnotneedtoresetpassword": []string{"false"},
-		"Authorization":                    []string{"Bearer " + auth.Token},
-	}
+	u, _ := url.Parse(c.resourceURL(auth, apiUsers, id))
+	headers := scimBodyHeaders(auth)
+	headers.Set("usershouldnotneedtoresetpassword", strconv.FormatBool(c.skipPasswordReset))
 
 	patchRequest := UserSCIMPatchRequest{
-		Schemas:    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		Schemas:    []string{patchRequestSchema},
 		Operations: operations,
 	}
 
@@ -342,27 +482,235 @@ func (c *UserClient) UpdateUser(ctx context.Context, auth *config.AuthConfig, us
 		vc.Logger.Errorf("unable to update user; err=%v", err)
 		return fmt.Errorf("unable to update user; err=%v", err)
 	}
-	if response.StatusCode != http.StatusNoContent {
+
+	switch response.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		c.forgetUserID(auth, userName)
 		vc.Logger.Errorf("failed to update user; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return fmt.Errorf("failed to update user ; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return module.NewAPIError(response, fmt.Errorf("failed to update user; %w with userName %s", ErrUserNotFound, userName))
+	case http.StatusConflict:
+		vc.Logger.Errorf("failed to update user; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return module.NewAPIError(response, fmt.Errorf("failed to update user %s; %w", userName, ErrUserConflict))
+	}
+
+	if err := module.HandleCommonErrors(ctx, response, "unable to update user"); err != nil {
+		vc.Logger.Errorf("unable to update user; err=%s", err.Error())
+		return err
+	}
+
+	vc.Logger.Errorf("failed to update user; code=%d, body=%s", response.StatusCode, string(response.Body))
+	return module.NewAPIError(response, fmt.Errorf("failed to update user ; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body))))
+}
+
+// SetUserActive enables or disables the account of the user.
+func (c *UserClient) SetUserActive(ctx context.Context, auth *config.AuthConfig, userName string, active bool) error {
+	return c.UpdateUser(ctx, auth, userName, []UserSCIMOpEntry{
+		{
+			Op:    "replace",
+			Path:  "active",
+			Value: active,
+		},
+	})
+}
+
+// validateUserOperations checks that each operation has a supported op, a well-formed
+// path and a value that matches the op.
+func validateUserOperations(operations []UserSCIMOpEntry) error {
+	if len(operations) == 0 {
+		return module.MakeSimpleError("at least one operation is required")
+	}
+
+	for i, op := range operations {
+		if len(op.Path) > 0 && !scimPathPattern.MatchString(op.Path) {
+			return module.MakeSimpleError(fmt.Sprintf("operation %d has an invalid path %q", i, op.Path))
+		}
+
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return module.MakeSimpleError(fmt.Sprintf("operation %d (%s) requires a value", i, op.Op))
+			}
+
+			// without a path, the value must be an object of attributes
+			if len(op.Path) == 0 {
+				if _, ok := op.Value.(map[string]interface{}); !ok {
+					return module.MakeSimpleError(fmt.Sprintf("operation %d (%s) requires an object value when no path is set", i, op.Op))
+				}
+			}
+		case "remove":
+			if len(op.Path) == 0 {
+				return module.MakeSimpleError(fmt.Sprintf("operation %d (remove) requires a path", i))
+			}
+		default:
+			return module.MakeSimpleError(fmt.Sprintf("operation %d has an unsupported op %q; expected add, remove or replace", i, op.Op))
+		}
 	}
 
 	return nil
 }
 
+// BatchResolveUserIDs resolves the usernames to user IDs using as few SCIM queries as
+// possible, combining up to 50 usernames in each filter.
+// The returned map is keyed by the usernames provided. An error listing every
+// username that could not be resolved is returned if any are missing.
+func (c *UserClient) BatchResolveUserIDs(ctx context.Context, auth *config.AuthConfig, usernames []string) (map[string]string, error) {
+	vc := config.GetVerifyContext(ctx)
+	headers := scimHeaders(auth)
+
+	// dedupe the input
+	pending := []string{}
+	seen := map[string]bool{}
+	for _, name := range usernames {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		pending = append(pending, name)
+	}
+
+	ids := map[string]string{}
+	cache := c.cache.Load()
+	if cache != nil {
+		uncached := []string{}
+		for _, name := range pending {
+			if id, ok := cache.get(auth.Tenant, name); ok {
+				ids[name] = id
+			} else {
+				uncached = append(uncached, name)
+			}
+		}
+		pending = uncached
+	}
+
+	for start := 0; start < len(pending); start += userFilterChunkSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		end := start + userFilterChunkSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+
+		chunk := pending[start:end]
+		clauses := make([]Filter, 0, len(chunk))
+		for _, name := range chunk {
+			clauses = append(clauses, Eq("userName", name))
+		}
+
+		u, _ := url.Parse(c.resourceURL(auth, apiUsers))
+		q := u.Query()
+		q.Set("filter", Or(clauses...).String())
+		q.Set("count", strconv.Itoa(len(chunk)))
+		u.RawQuery = q.Encode()
+
+		response, err := c.client.Get(ctx, u, headers)
+		if err != nil {
+			vc.Logger.Errorf("unable to get the Users; err=%s", err.Error())
+			return nil, err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			if err := module.HandleCommonErrors(ctx, response, "unable to get Users"); err != nil {
+				vc.Logger.Errorf("unable to get the Users; err=%s", err.Error())
+				return nil, err
+			}
+
+			vc.Logger.Errorf("unable to get the Users; code=%d, body=%s", response.StatusCode, string(response.Body))
+			return nil, module.NewAPIError(response, fmt.Errorf("unable to get the Users"))
+		}
+
+		usersResponse := &UserListResponse{}
+		if err := json.Unmarshal(response.Body, usersResponse); err != nil {
+			vc.Logger.Errorf("unable to get the Users; err=%s, body=%s", err, string(response.Body))
+			return nil, fmt.Errorf("unable to get the Users")
+		}
+
+		// userName comparisons are case-insensitive in SCIM
+		found := map[string]string{}
+		for _, user := range usersResponse.Users {
+			found[strings.ToLower(user.UserName)] = user.Id
+		}
+
+		for _, name := range chunk {
+			if id, ok := found[strings.ToLower(name)]; ok {
+				ids[name] = id
+				if cache != nil {
+					cache.put(auth.Tenant, name, id)
+				}
+			}
+		}
+	}
+
+	missing := []string{}
+	for _, name := range pending {
+		if _, ok := ids[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return ids, fmt.Errorf("%w with userName %s", ErrUserNotFound, strings.Join(missing, ", "))
+	}
+
+	return ids, nil
+}
+
+func (c *UserClient) getUserByID(ctx context.Context, auth *config.AuthConfig, id string) (*User, error) {
+	u, _ := url.Parse(c.resourceURL(auth, apiUsers, id))
+	headers := scimHeaders(auth)
+
+	response, err := c.client.Get(ctx, u, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if err := module.HandleCommonErrors(ctx, response, "unable to get User"); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("unable to get the User with id %s; code=%d", id, response.StatusCode)
+	}
+
+	user := &User{}
+	if err := json.Unmarshal(response.Body, user); err != nil {
+		return nil, fmt.Errorf("unable to get the User with id %s", id)
+	}
+
+	return user, nil
+}
+
+// forgetUserID removes the username from the cache, if the cache is enabled.
+func (c *UserClient) forgetUserID(auth *config.AuthConfig, name string) {
+	if cache := c.cache.Load(); cache != nil {
+		cache.remove(auth.Tenant, name)
+	}
+}
+
 func (c *UserClient) getUserId(ctx context.Context, auth *config.AuthConfig, name string) (string, error) {
 	vc := config.GetVerifyContext(ctx)
-	headers := http.Header{
-		"Accept":        []string{"application/scim+json"},
-		"Authorization": []string{"Bearer " + auth.Token},
+	cache := c.cache.Load()
+	if cache != nil {
+		if id, ok := cache.get(auth.Tenant, name); ok {
+			return id, nil
+		}
 	}
 
-	u, _ := url.Parse(fmt.Sprintf("https://%s/%s", auth.Tenant, apiUsers))
+	headers := scimHeaders(auth)
+
+	u, _ := url.Parse(c.resourceURL(auth, apiUsers))
 	q := u.Query()
-	q.Set("filter", fmt.Sprintf(`userName eq "%s"`, name))
+	q.Set("filter", Eq("userName", name).String())
 	u.RawQuery = q.Encode()
 
-	response, _ := c.client.Get(ctx, u, headers)
+	response, err := c.client.Get(ctx, u, headers)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the User with userName %s; err=%s", name, err.Error())
+		return "", err
+	}
 
 	if response.StatusCode != http.StatusOK {
 		if err := module.HandleCommonErrors(ctx, response, "unable to get User"); err != nil {
@@ -378,7 +726,7 @@ func (c *UserClient) getUserId(ctx context.Context, auth *config.AuthConfig, nam
 
 	resources, ok := data["Resources"].([]interface{})
 	if !ok || len(resources) == 0 {
-		return "", fmt.Errorf("no user found with userName %s", name)
+		return "", fmt.Errorf("%w with userName %s", ErrUserNotFound, name)
 	}
 
 	firstResource, ok := resources[0].(map[string]interface{})
@@ -392,5 +740,9 @@ func (c *UserClient) getUserId(ctx context.Context, auth *config.AuthConfig, nam
 		return "", fmt.Errorf("ID not found or invalid type")
 	}
 
+	if cache != nil {
+		cache.put(auth.Tenant, name, id)
+	}
+
 	return id, nil
 }
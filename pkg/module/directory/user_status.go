@@ -0,0 +1,235 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+)
+
+// DeactivateUserResult describes the changes made by DeactivateUser.
+type DeactivateUserResult struct {
+	// Deactivated is false if the account was already inactive.
+	Deactivated bool
+
+	// RemovedGroups are the groups the user was removed from.
+	RemovedGroups []UserGroup
+
+	// Failed maps the ID of each group the user could not be removed from to the error.
+	Failed map[string]error
+}
+
+// Err returns the failures as a single error, or nil if there are none.
+func (r *DeactivateUserResult) Err() error {
+	return joinFailures(r.Failed)
+}
+
+// ActivateUser enables the account of the user.
+func (c *UserClient) ActivateUser(ctx context.Context, auth *config.AuthConfig, userName string) error {
+	return c.SetUserActive(ctx, auth, userName, true)
+}
+
+// DeactivateUser disables the account of the user. If removeFromGroups is set, the
+// user is then removed from every group it belongs to. The account is disabled
+// first so that a failure to remove a membership never leaves the account active;
+// such failures are reported in the result and by its Err method.
+func (c *UserClient) DeactivateUser(ctx context.Context, auth *config.AuthConfig, userName string, removeFromGroups bool) (*DeactivateUserResult, error) {
+	vc := config.GetVerifyContext(ctx)
+	id, err := c.getUserId(ctx, auth, userName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the user ID; err=%s", err.Error())
+		return nil, fmt.Errorf("unable to get the user ID; err=%w", err)
+	}
+
+	user, err := c.getUserByID(ctx, auth, id)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the User; err=%s", err.Error())
+		return nil, err
+	}
+
+	result := &DeactivateUserResult{
+		Failed: map[string]error{},
+	}
+
+	if user.Active {
+		if err := c.SetUserActive(ctx, auth, userName, false); err != nil {
+			return nil, err
+		}
+		result.Deactivated = true
+	}
+
+	if !removeFromGroups {
+		return result, nil
+	}
+
+	groups, err := c.getUserGroupsByID(ctx, auth, id)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the groups of the User; err=%s", err.Error())
+		return result, err
+	}
+
+	for _, group := range groups {
+		if err := c.removeFromGroup(ctx, auth, group.groupID(), id); err != nil {
+			result.Failed[group.groupID()] = err
+			continue
+		}
+
+		result.RemovedGroups = append(result.RemovedGroups, group)
+	}
+
+	return result, nil
+}
+
+// groupID returns the ID of the group, which Verify sets in either id or value.
+func (g UserGroup) groupID() string {
+	if len(g.ID) > 0 {
+		return g.ID
+	}
+
+	return g.Value
+}
+
+// GetUserGroups returns the groups the user with the username belongs to, which is
+// empty if the user is not a member of any group.
+func (c *UserClient) GetUserGroups(ctx context.Context, auth *config.AuthConfig, userName string) ([]UserGroup, error) {
+	vc := config.GetVerifyContext(ctx)
+	id, err := c.getUserId(ctx, auth, userName)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the user ID; err=%s", err.Error())
+		return nil, fmt.Errorf("unable to get the user ID; err=%w", err)
+	}
+
+	groups, err := c.getUserGroupsByID(ctx, auth, id)
+	if err != nil {
+		vc.Logger.Errorf("unable to get the groups of the User; err=%s", err.Error())
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// getUserGroupsByID returns the groups the user with the ID belongs to. They are
+// read from the groups attribute of the user, and if the tenant does not return the
+// attribute, the groups are searched for the user instead.
+func (c *UserClient) getUserGroupsByID(ctx context.Context, auth *config.AuthConfig, id string) ([]UserGroup, error) {
+	u, _ := url.Parse(c.resourceURL(auth, apiUsers, id))
+	q := u.Query()
+	q.Set("attributes", "groups")
+	u.RawQuery = q.Encode()
+	headers := scimHeaders(auth)
+
+	response, err := c.client.Get(ctx, u, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, module.NewAPIError(response, fmt.Errorf("%w with id %s", ErrUserNotFound, id))
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if err := module.HandleCommonErrors(ctx, response, "unable to get User"); err != nil {
+			return nil, err
+		}
+
+		return nil, module.NewAPIError(response, fmt.Errorf("unable to get the groups of the User with id %s; code=%d", id, response.StatusCode))
+	}
+
+	user := struct {
+		Groups *[]UserGroup `json:"groups"`
+	}{}
+	if err := json.Unmarshal(response.Body, &user); err != nil {
+		return nil, fmt.Errorf("unable to get the groups of the User with id %s", id)
+	}
+
+	if user.Groups == nil {
+		return c.searchUserGroups(ctx, auth, id)
+	}
+
+	return *user.Groups, nil
+}
+
+// searchUserGroups returns the groups that have the user with the ID as a member.
+func (c *UserClient) searchUserGroups(ctx context.Context, auth *config.AuthConfig, id string) ([]UserGroup, error) {
+	headers := scimHeaders(auth)
+	groups := []UserGroup{}
+	for startIndex := 1; ; {
+		u, _ := url.Parse(c.resourceURL(auth, apiGroups))
+		q := u.Query()
+		q.Set("filter", Eq("members.value", id).String())
+		q.Set("attributes", "id,displayName")
+		q.Set("count", strconv.Itoa(defaultGroupPageSize))
+		q.Set("startIndex", strconv.Itoa(startIndex))
+		u.RawQuery = q.Encode()
+
+		response, err := c.client.Get(ctx, u, headers)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			if err := module.HandleCommonErrors(ctx, response, "unable to get Groups"); err != nil {
+				return nil, err
+			}
+
+			return nil, module.NewAPIError(response, fmt.Errorf("unable to get the groups of the User with id %s; code=%d", id, response.StatusCode))
+		}
+
+		page := &GroupListResponse{}
+		if err := json.Unmarshal(response.Body, page); err != nil {
+			return nil, fmt.Errorf("unable to get the groups of the User with id %s", id)
+		}
+
+		for _, group := range page.Groups {
+			groups = append(groups, UserGroup{
+				ID:          group.Id,
+				DisplayName: group.DisplayName,
+				Value:       group.Id,
+			})
+		}
+
+		startIndex += len(page.Groups)
+		if len(page.Groups) == 0 || startIndex > page.TotalResults {
+			return groups, nil
+		}
+	}
+}
+
+// removeFromGroup removes the user with the ID from the members of the group.
+func (c *UserClient) removeFromGroup(ctx context.Context, auth *config.AuthConfig, groupID string, userID string) error {
+	u, _ := url.Parse(c.resourceURL(auth, apiGroups, groupID))
+	headers := scimBodyHeaders(auth)
+
+	b, err := json.Marshal(GroupSCIMPatchRequest{
+		Schemas: []string{patchRequestSchema},
+		Operations: []GroupSCIMOpEntry{
+			{
+				Op:   "remove",
+				Path: fmt.Sprintf("members[value eq %s]", quoteFilterValue(userID)),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to marshal the patch request; err=%v", err)
+	}
+
+	response, err := c.client.Patch(ctx, u, headers, b)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		if err := module.HandleCommonErrors(ctx, response, "unable to update group"); err != nil {
+			return err
+		}
+
+		return module.NewAPIError(response, fmt.Errorf("unable to remove the User from the group %s; code=%d", groupID, response.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package directory
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+)
+
+func TestGetUsersEmpty(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, `{"totalResults":0,"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"]}`)
+	client := NewUserClientWithClient(fake)
+
+	users, _, err := client.GetUsers(testContext(t), testAuth, `userName eq "nobody"`, "", "", "")
+	if err != nil {
+		t.Fatalf("GetUsers() err = %v, want nil", err)
+	}
+
+	if users.TotalResults != 0 || len(users.Users) != 0 {
+		t.Errorf("GetUsers() = %d users with totalResults %d, want none", len(users.Users), users.TotalResults)
+	}
+}
+
+func TestGetUsersQuery(t *testing.T) {
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Users", http.StatusOK, `{"totalResults":42,"Resources":[{"id":"u1","userName":"jdoe"}]}`)
+	client := NewUserClientWithClient(fake)
+
+	filter := `userName sw "j d" and emails.value co "&x=1"`
+	users, _, err := client.GetUsers(testContext(t), testAuth, filter, "userName", "10", "11")
+	if err != nil {
+		t.Fatalf("GetUsers() err = %v, want nil", err)
+	}
+
+	if users.TotalResults != 42 {
+		t.Errorf("GetUsers() totalResults = %d, want 42", users.TotalResults)
+	}
+
+	request := fake.Requests()[0]
+	q := request.URL.Query()
+	want := map[string]string{
+		"filter":     filter,
+		"sortBy":     "userName",
+		"count":      "10",
+		"startIndex": "11",
+	}
+	for name, value := range want {
+		if got := q.Get(name); got != value {
+			t.Errorf("query %s = %q, want %q", name, got, value)
+		}
+	}
+
+	// the filter must be encoded so that its spaces, quotes and ampersand do not
+	// break the query
+	if strings.ContainsAny(request.URL.RawQuery, ` "`) || strings.Count(request.URL.RawQuery, "&") != 3 {
+		t.Errorf("query %q is not encoded", request.URL.RawQuery)
+	}
+}
+
+func TestGetUsersInvalidStartIndex(t *testing.T) {
+	fake := directorytest.NewFakeClient()
+	client := NewUserClientWithClient(fake)
+
+	if _, _, err := client.GetUsers(testContext(t), testAuth, "", "", "", "0"); err == nil {
+		t.Error("GetUsers() err = nil, want an error for startIndex 0")
+	}
+
+	if len(fake.Requests()) != 0 {
+		t.Errorf("GetUsers() sent %d requests, want none", len(fake.Requests()))
+	}
+}
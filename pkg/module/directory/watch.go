@@ -0,0 +1,163 @@
+package directory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/config"
+	"github.com/ibm-security-verify/verifyctl/pkg/module"
+)
+
+// GroupEvent is emitted by WatchGroups when a group changes or a poll fails.
+type GroupEvent struct {
+	Group *Group
+	Err   error
+}
+
+// WatchGroups polls the groups matching the filter every interval and emits the
+// groups that were added or modified since the previous poll. This is polling, not
+// a server push, so changes are seen up to one interval late and deleted groups are
+// not reported.
+//
+// The first poll records the current state without emitting anything. A failed poll
+// is emitted as an event with Err set and watching continues. The channel is closed
+// once the context is cancelled. If the interval is not positive, a single event with
+// Err set is emitted and the channel is closed without polling.
+func (c *GroupClient) WatchGroups(ctx context.Context, auth *config.AuthConfig, interval time.Duration, filter string) <-chan GroupEvent {
+	if interval <= 0 {
+		events := make(chan GroupEvent, 1)
+		events <- GroupEvent{Err: fmt.Errorf("invalid interval %s; must be greater than 0", interval)}
+		close(events)
+		return events
+	}
+
+	events := make(chan GroupEvent)
+	go func() {
+		defer close(events)
+
+		w := &groupWatcher{
+			client:   c,
+			auth:     auth,
+			filter:   filter,
+			versions: map[string]string{},
+			useSince: true,
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		first := true
+		for {
+			changed, err := w.poll(ctx)
+			if err != nil && ctx.Err() == nil {
+				if !sendGroupEvent(ctx, events, GroupEvent{Err: err}) {
+					return
+				}
+			}
+
+			if !first {
+				for i := range changed {
+					if !sendGroupEvent(ctx, events, GroupEvent{Group: &changed[i]}) {
+						return
+					}
+				}
+			}
+
+			// the state is only recorded once a poll succeeds
+			if err == nil {
+				first = false
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events
+}
+
+type groupWatcher struct {
+	client *GroupClient
+	auth   *config.AuthConfig
+	filter string
+
+	// versions holds the last seen version of each group, keyed by ID
+	versions map[string]string
+
+	// since is the latest modification time seen, used to only list groups
+	// modified at or after it. Groups modified at the same time that were already
+	// seen are dropped by their version.
+	since    string
+	useSince bool
+}
+
+// poll lists the groups and returns those whose version differs from the last poll.
+func (w *groupWatcher) poll(ctx context.Context) ([]Group, error) {
+	vc := config.GetVerifyContext(ctx)
+
+	groups, err := w.client.getAllGroups(ctx, w.auth, w.sinceFilter(), "")
+	if err != nil && w.useSince && len(w.since) > 0 && isInvalidFilter(err) {
+		// the tenant does not support filtering on meta.lastModified. Other errors
+		// may be transient, so the filter is tried again on the next poll.
+		vc.Logger.Warnf("unable to list the groups modified since %s; listing all groups instead; err=%s", w.since, err.Error())
+		w.useSince = false
+		groups, err = w.client.getAllGroups(ctx, w.auth, w.filter, "")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []Group{}
+	for _, group := range groups {
+		version := group.Meta.Version
+		if len(version) == 0 {
+			version = group.Meta.LastModified
+		}
+
+		if previous, ok := w.versions[group.Id]; !ok || previous != version {
+			w.versions[group.Id] = version
+			changed = append(changed, group)
+		}
+
+		if group.Meta.LastModified > w.since {
+			w.since = group.Meta.LastModified
+		}
+	}
+
+	return changed, nil
+}
+
+func (w *groupWatcher) sinceFilter() string {
+	if !w.useSince || len(w.since) == 0 {
+		return w.filter
+	}
+
+	since := Ge("meta.lastModified", w.since)
+	if len(w.filter) == 0 {
+		return since.String()
+	}
+
+	return And(Raw(w.filter), since).String()
+}
+
+// isInvalidFilter returns true if the tenant rejected the filter of the request.
+func isInvalidFilter(err error) bool {
+	var scimError *module.SCIMError
+	return module.StatusCode(err) == http.StatusBadRequest && errors.As(err, &scimError) && scimError.ScimType == "invalidFilter"
+}
+
+func sendGroupEvent(ctx context.Context, events chan<- GroupEvent, event GroupEvent) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case events <- event:
+		return true
+	}
+}
@@ -0,0 +1,154 @@
+package directory
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ibm-security-verify/verifyctl/pkg/module/directory/directorytest"
+)
+
+func TestWatchGroupsEmitsChangedGroups(t *testing.T) {
+	ctx, cancel := context.WithTimeout(testContext(t), 5*time.Second)
+	defer cancel()
+
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":2,"Resources":[{"id":"g1","meta":{"version":"1","lastModified":"2024-01-01T00:00:00Z"}},{"id":"g2","meta":{"version":"1","lastModified":"2024-01-01T00:00:00Z"}}]}`).
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":1,"Resources":[{"id":"g2","meta":{"version":"2","lastModified":"2024-01-02T00:00:00Z"}}]}`)
+	client := NewGroupClientWithClient(fake)
+
+	events := client.WatchGroups(ctx, testAuth, 5*time.Millisecond, "")
+
+	// the first poll only records the groups, so the first event is the change to g2
+	event := <-events
+	if event.Err != nil || event.Group == nil || event.Group.Id != "g2" || event.Group.Meta.Version != "2" {
+		t.Fatalf("first event = %+v, want version 2 of g2", event)
+	}
+
+	if filter := fake.Requests()[1].URL.Query().Get("filter"); filter != `meta.lastModified ge "2024-01-01T00:00:00Z"` {
+		t.Errorf("second poll filter = %s, want the groups modified at or after the last one seen", filter)
+	}
+
+	cancel()
+	for event := range events {
+		if event.Group != nil && event.Group.Id == "g2" {
+			t.Errorf("g2 was reported again although its version did not change")
+		}
+	}
+}
+
+func TestWatchGroupsFirstPollFails(t *testing.T) {
+	ctx, cancel := context.WithTimeout(testContext(t), 5*time.Second)
+	defer cancel()
+
+	fake := directorytest.NewFakeClient().
+		On(http.MethodGet, "/v2.0/Groups", http.StatusInternalServerError, "").
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":2,"Resources":[{"id":"g1","meta":{"version":"1","lastModified":"2024-01-01T00:00:00Z"}},{"id":"g2","meta":{"version":"1","lastModified":"2024-01-01T00:00:00Z"}}]}`).
+		On(http.MethodGet, "/v2.0/Groups", http.StatusOK, `{"totalResults":2,"Resources":[{"id":"g1","meta":{"version":"1","lastModified":"2024-01-01T00:00:00Z"}},{"id":"g2","meta":{"version":"2","lastModified":"2024-01-01T00:00:00Z"}}]}`)
+	client := NewGroupClientWithClient(fake)
+
+	events := client.WatchGroups(ctx, testAuth, 5*time.Millisecond, "")
+
+	first := <-events
+	if first.Err == nil {
+		t.Fatalf("first event = %+v, want the error of the failed poll", first)
+	}
+
+	// the second poll only records the groups, so the next event is the change to
+	// g2, which was modified in the same second as the last group seen
+	second := <-events
+	if second.Err != nil || second.Group == nil || second.Group.Id != "g2" || second.Group.Meta.Version != "2" {
+		t.Fatalf("second event = %+v, want version 2 of g2", second)
+	}
+
+	if filter := fake.Requests()[2].URL.Query().Get("filter"); filter != `meta.lastModified ge "2024-01-01T00:00:00Z"` {
+		t.Errorf("third poll filter = %s, want the groups modified at or after the last one seen", filter)
+	}
+}
+
+func TestWatchGroupsInvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		t.Run(interval.String(), func(t *testing.T) {
+			fake := directorytest.NewFakeClient()
+			client := NewGroupClientWithClient(fake)
+
+			events := client.WatchGroups(testContext(t), testAuth, interval, "")
+
+			if event := <-events; event.Err == nil {
+				t.Errorf("first event = %+v, want an error for the interval", event)
+			}
+
+			if event, ok := <-events; ok {
+				t.Errorf("second event = %+v, want the channel to be closed", event)
+			}
+
+			if n := len(fake.Requests()); n != 0 {
+				t.Errorf("sent %d requests, want none", n)
+			}
+		})
+	}
+}
+
+func TestWatchGroupsSinceFilterFallback(t *testing.T) {
+	const (
+		g1v1 = `{"totalResults":1,"Resources":[{"id":"g1","meta":{"version":"1","lastModified":"2024-01-01T00:00:00Z"}}]}`
+		g1v2 = `{"totalResults":1,"Resources":[{"id":"g1","meta":{"version":"2","lastModified":"2024-01-02T00:00:00Z"}}]}`
+	)
+	since := `meta.lastModified ge "2024-01-01T00:00:00Z"`
+
+	tests := []struct {
+		name string
+		// failure is the response to the first poll filtered on meta.lastModified
+		failure string
+		status  int
+		// filters are the filters sent by the polls after the first
+		filters []string
+		wantErr bool
+	}{
+		{
+			name:    "transient failure",
+			status:  http.StatusServiceUnavailable,
+			filters: []string{since, since},
+			wantErr: true,
+		},
+		{
+			name:    "filter not supported",
+			failure: `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"400","scimType":"invalidFilter","detail":"meta.lastModified is not supported"}`,
+			status:  http.StatusBadRequest,
+			filters: []string{since, ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(testContext(t), 5*time.Second)
+			defer cancel()
+
+			fake := directorytest.NewFakeClient().
+				On(http.MethodGet, "/v2.0/Groups", http.StatusOK, g1v1).
+				On(http.MethodGet, "/v2.0/Groups", tt.status, tt.failure).
+				On(http.MethodGet, "/v2.0/Groups", http.StatusOK, g1v2)
+			client := NewGroupClientWithClient(fake)
+
+			events := client.WatchGroups(ctx, testAuth, 5*time.Millisecond, "")
+
+			if tt.wantErr {
+				if event := <-events; event.Err == nil {
+					t.Fatalf("first event = %+v, want the error of the failed poll", event)
+				}
+			}
+
+			if event := <-events; event.Err != nil || event.Group == nil || event.Group.Meta.Version != "2" {
+				t.Fatalf("event = %+v, want version 2 of g1", event)
+			}
+
+			requests := fake.Requests()
+			for i, want := range tt.filters {
+				if got := requests[i+1].URL.Query().Get("filter"); got != want {
+					t.Errorf("request %d filter = %q, want %q", i+1, got, want)
+				}
+			}
+		})
+	}
+}
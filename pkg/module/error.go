@@ -1,5 +1,23 @@
 package module
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
+)
+
+var (
+	// ErrUnauthorized is returned when the server rejects the token, usually because
+	// the session has expired.
+	ErrUnauthorized = errors.New("Login again.")
+
+	// ErrForbidden is returned when the token is valid but lacks the entitlements
+	// required by the request.
+	ErrForbidden = errors.New("You are not allowed to make this request. Check the client or application entitlements.")
+)
+
 type SimpleError struct {
 	Message string
 }
@@ -13,3 +31,63 @@ func MakeSimpleError(message string) error {
 		Message: message,
 	}
 }
+
+// APIError is returned when the tenant responds with an error. It keeps the status
+// code, headers and body of the response for diagnostics and wraps the error that
+// describes it, so errors.Is and errors.As see through it.
+type APIError struct {
+	StatusCode int
+	Headers    http.Header
+	Body       []byte
+	// SentRequestID is the ID sent in the X-Request-ID header of the request.
+	SentRequestID string
+	Err           error
+}
+
+// NewAPIError returns an APIError for the response, described by err.
+func NewAPIError(response *xhttp.Response, err error) *APIError {
+	return &APIError{
+		StatusCode:    response.StatusCode,
+		Headers:       response.Headers,
+		Body:          response.Body,
+		SentRequestID: response.RequestID,
+		Err:           err,
+	}
+}
+
+// Error includes the request ID so that it shows up wherever the error is logged.
+func (e *APIError) Error() string {
+	if id := e.RequestID(); len(id) > 0 {
+		return fmt.Sprintf("%s (requestId=%s)", e.Err.Error(), id)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// RequestID returns the identifier the tenant assigned to the request, which is
+// useful when raising a support ticket. If the tenant did not return one, the ID
+// sent with the request is returned.
+func (e *APIError) RequestID() string {
+	for _, name := range []string{"X-Request-Id", "X-Correlation-Id", "X-Transaction-Id"} {
+		if id := e.Headers.Get(name); len(id) > 0 {
+			return id
+		}
+	}
+
+	return e.SentRequestID
+}
+
+// StatusCode returns the HTTP status code carried by an APIError in the chain of
+// err, or 0 if there is none.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+
+	return 0
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ibm-security-verify/verifyctl/pkg/module"
 	xhttp "github.com/ibm-security-verify/verifyctl/pkg/util/http"
 	typesx "github.com/ibm-security-verify/verifyctl/pkg/util/types"
+	"github.com/ibm-security-verify/verifyctl/x/logx"
 )
 
 const (
@@ -251,7 +252,7 @@ func (c *ApiClient) UpdateAPIClient(ctx context.Context, auth *config.AuthConfig
 	}
 	if response.StatusCode != http.StatusNoContent {
 		vc.Logger.Errorf("failed to update API client; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return fmt.Errorf("failed to update API client ; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return fmt.Errorf("failed to update API client ; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body)))
 	}
 
 	return nil
@@ -356,7 +357,7 @@ func (c *ApiClient) DeleteAPIClient(ctx context.Context, auth *config.AuthConfig
 		}
 
 		vc.Logger.Errorf("unable to delete the API client; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return fmt.Errorf("unable to delete the API client; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return fmt.Errorf("unable to delete the API client; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body)))
 	}
 
 	return nil
@@ -380,7 +381,7 @@ func (c *ApiClient) DeleteAPIClientById(ctx context.Context, auth *config.AuthCo
 			return fmt.Errorf("unable to delete the API client; err=%s", err.Error())
 		}
 		vc.Logger.Errorf("unable to delete the API client; code=%d, body=%s", response.StatusCode, string(response.Body))
-		return fmt.Errorf("unable to delete the API client; code=%d, body=%s", response.StatusCode, string(response.Body))
+		return fmt.Errorf("unable to delete the API client; code=%d, body=%s", response.StatusCode, logx.Redact(string(response.Body)))
 	}
 	return nil
 }
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen is returned without sending the request while the circuit
+	// breaker is open.
+	ErrCircuitOpen = errors.New("the tenant is failing; requests are paused")
+)
+
+// CircuitBreakerPolicy controls when the circuit breaker stops sending requests.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures after which the
+	// breaker opens. Connection failures and 5xx responses count as failures.
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before a single request is let
+	// through to probe whether the tenant has recovered.
+	Cooldown time.Duration
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuitBreaker struct {
+	mu       sync.Mutex
+	policy   CircuitBreakerPolicy
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+// WithCircuitBreaker fails requests fast with ErrCircuitOpen once the tenant has
+// failed FailureThreshold times in a row. After the cooldown, one request is sent
+// as a probe; if it succeeds the breaker closes, and otherwise it opens again.
+// Requests still return as soon as their context is done.
+func WithCircuitBreaker(policy CircuitBreakerPolicy) ClientOption {
+	return func(c *defaultClientx) {
+		if policy.FailureThreshold < 1 {
+			policy.FailureThreshold = 5
+		}
+
+		if policy.Cooldown <= 0 {
+			policy.Cooldown = 30 * time.Second
+		}
+
+		c.breaker = &circuitBreaker{
+			policy: policy,
+		}
+	}
+}
+
+// allow reports whether a request may be sent.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.policy.Cooldown {
+			return false
+		}
+
+		b.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// a probe is already in flight
+		return false
+	}
+
+	return true
+}
+
+// record updates the breaker with the outcome of a request that allow let through.
+func (b *circuitBreaker) record(response *Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// a cancelled request says nothing about the tenant, so a cancelled probe
+	// lets the next request probe instead
+	if errors.Is(err, context.Canceled) {
+		if b.state == circuitHalfOpen {
+			b.state = circuitOpen
+		}
+		return
+	}
+
+	failed := err != nil || response.StatusCode >= http.StatusInternalServerError
+	if !failed {
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.policy.FailureThreshold {
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+	}
+}
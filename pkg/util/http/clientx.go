@@ -30,6 +30,9 @@ type Clientx interface {
 
 	// Delete makes a HTTP DELETE call and returns the response
 	Delete(ctx context.Context, url *url.URL, headers http.Header) (*Response, error)
+
+	// Head makes a HTTP HEAD call and returns the response, which has no body
+	Head(ctx context.Context, url *url.URL, headers http.Header) (*Response, error)
 }
 
 // Response includes the StatusCode, Body and Headers of a request.
@@ -41,4 +44,6 @@ type Response struct {
 	Body []byte
 	// Headers returns the HTTP response headers.
 	Headers http.Header
+	// RequestID is the ID sent in the X-Request-ID header of the request.
+	RequestID string
 }
@@ -2,16 +2,33 @@ package http
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
+const (
+	// DefaultRequestTimeout bounds each call made through the client when the
+	// caller's context has no deadline.
+	DefaultRequestTimeout = 2 * time.Minute
+
+	// DefaultMaxResponseSize bounds the body of each response read by the client.
+	DefaultMaxResponseSize int64 = 64 << 20
+)
+
 var (
+	// ErrResponseTooLarge is returned when a response body exceeds the maximum
+	// size set with WithMaxResponseSize. The request is not retried.
+	ErrResponseTooLarge = errors.New("the response body is too large")
+
 	defaultClient *http.Client = &http.Client{
 		Transport:     http.DefaultTransport,
 		Timeout:       30 * time.Minute,
@@ -20,148 +37,254 @@ var (
 )
 
 type defaultClientx struct {
-	client *http.Client
+	client    *http.Client
+	tls       *tls.Config
+	proxy     *url.URL
+	timeout   time.Duration
+	retry     *RetryPolicy
+	limiter   *rateLimiter
+	logger    *requestLogger
+	observer  Observer
+	breaker   *circuitBreaker
+	userAgent string
+	headers   http.Header
+	maxBody   int64
 }
 
-func NewDefaultClient() Clientx {
-	return &defaultClientx{
-		client: defaultClient,
+// ClientOption configures the client returned by NewDefaultClient.
+type ClientOption func(*defaultClientx)
+
+// WithTimeout sets the timeout applied to each call, including any retries, when
+// the caller's context has no deadline. Callers that set their own deadline keep
+// control of it. A timeout of zero disables the default.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *defaultClientx) {
+		c.timeout = timeout
 	}
 }
 
-// Get makes a HTTP GET call and returns the response
-func (c *defaultClientx) Get(ctx context.Context, url *url.URL, headers http.Header) (*Response, error) {
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
-	if err != nil {
-		return nil, err
+// WithMaxResponseSize sets the largest response body, after decompression, that
+// the client reads, so that a misbehaving endpoint cannot exhaust memory. A size
+// of zero or less restores DefaultMaxResponseSize.
+func WithMaxResponseSize(size int64) ClientOption {
+	return func(c *defaultClientx) {
+		c.maxBody = size
 	}
+}
 
-	for k, v := range headers {
-		request.Header.Add(k, v[0])
+// NewDefaultClient returns a Clientx backed by the standard library HTTP client.
+// Without options, requests are made once, are not retried and are not rate limited,
+// each call is bounded by DefaultRequestTimeout, proxies are taken from the
+// environment, the User-Agent is DefaultUserAgent, and response bodies are limited
+// to DefaultMaxResponseSize.
+func NewDefaultClient(opts ...ClientOption) Clientx {
+	c := &defaultClientx{
+		client:  defaultClient,
+		timeout: DefaultRequestTimeout,
 	}
 
-	response, err := c.client.Do(request)
-	if err != nil {
-		return nil, err
+	for _, opt := range opts {
+		opt(c)
 	}
 
-	defer response.Body.Close()
+	if len(c.userAgent) == 0 {
+		c.userAgent = DefaultUserAgent()
+	}
 
-	respObj := &Response{
-		StatusCode: response.StatusCode,
-		Headers:    response.Header,
+	if c.maxBody <= 0 {
+		c.maxBody = DefaultMaxResponseSize
 	}
 
-	if response.Body != nil {
-		resBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("unable to extract the body")
-		}
+	if c.tls != nil || c.proxy != nil {
+		c.client = c.newHTTPClient()
+	}
 
-		respObj.Body = resBody
+	return c
+}
+
+// newHTTPClient returns a client like the default one with a transport that uses
+// the configured TLS and proxy settings.
+func (c *defaultClientx) newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = c.tls
+	transport.Proxy = c.proxyFunc()
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       defaultClient.Timeout,
+		CheckRedirect: defaultClient.CheckRedirect,
 	}
+}
 
-	return respObj, nil
+// Get makes a HTTP GET call and returns the response
+func (c *defaultClientx) Get(ctx context.Context, url *url.URL, headers http.Header) (*Response, error) {
+	return c.do(ctx, http.MethodGet, url, headers, nil)
 }
 
 // Post makes a HTTP POST call and returns the response
 func (c *defaultClientx) Post(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*Response, error) {
-	bodyReader := bytes.NewReader([]byte(body))
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url.String(), bodyReader)
+	return c.do(ctx, http.MethodPost, url, headers, body)
+}
+
+// PostMultipart makes a HTTP POST call with content-type set to multipart/form-data and returns the response
+func (c *defaultClientx) PostMultipart(ctx context.Context, url *url.URL, headers http.Header, files map[string][]byte, fields map[string]string) (*Response, error) {
+	body, err := multipartBody(files, fields)
 	if err != nil {
 		return nil, err
 	}
 
-	for k, v := range headers {
-		request.Header.Add(k, v[0])
-	}
+	return c.do(ctx, http.MethodPost, url, multipartHeaders(headers), body)
+}
 
-	response, err := c.client.Do(request)
+// Put makes a HTTP PUT call and returns the response
+func (c *defaultClientx) Put(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*Response, error) {
+	return c.do(ctx, http.MethodPut, url, headers, body)
+}
+
+// PutMultipart makes a HTTP PUT call with content-type set to multipart/form-data and returns the response
+func (c *defaultClientx) PutMultipart(ctx context.Context, url *url.URL, headers http.Header, files map[string][]byte, fields map[string]string) (*Response, error) {
+	body, err := multipartBody(files, fields)
 	if err != nil {
 		return nil, err
 	}
 
-	defer response.Body.Close()
-
-	respObj := &Response{
-		StatusCode: response.StatusCode,
-		Headers:    response.Header,
-	}
-
-	if response.Body != nil {
-		resBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("unable to extract the body")
-		}
+	return c.do(ctx, http.MethodPut, url, multipartHeaders(headers), body)
+}
 
-		respObj.Body = resBody
-	}
+// Patch makes a HTTP PATCH call and returns the response
+func (c *defaultClientx) Patch(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*Response, error) {
+	return c.do(ctx, http.MethodPatch, url, headers, body)
+}
 
-	return respObj, nil
+// Delete makes a HTTP DELETE call and returns the response
+func (c *defaultClientx) Delete(ctx context.Context, url *url.URL, headers http.Header) (*Response, error) {
+	return c.do(ctx, http.MethodDelete, url, headers, nil)
 }
 
-// PostMultipart makes a HTTP POST call with content-type set to multipart/form-data and returns the response
-func (c *defaultClientx) PostMultipart(ctx context.Context, url *url.URL, headers http.Header, files map[string][]byte, fields map[string]string) (*Response, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	defer writer.Close()
+// Head makes a HTTP HEAD call and returns the response, which has no body
+func (c *defaultClientx) Head(ctx context.Context, url *url.URL, headers http.Header) (*Response, error) {
+	return c.do(ctx, http.MethodHead, url, headers, nil)
+}
 
-	for fileName, data := range files {
-		part, err := writer.CreateFormFile(fileName, fileName)
-		if err != nil {
-			return nil, err
-		}
-
-		_, err = io.Copy(part, bytes.NewReader(data))
-		if err != nil {
-			return nil, err
+// do sends the request, retrying it according to the retry policy if one is set.
+// If the context carries a TokenSource, the bearer token is refreshed as needed.
+// Every attempt is sent with the same request ID.
+func (c *defaultClientx) do(ctx context.Context, method string, url *url.URL, headers http.Header, body []byte) (*Response, error) {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	headers = withDefaultHeaders(ctx, headers, c.headers)
+	headers, requestID := withRequestID(ctx, headers)
+	headers = withUserAgent(headers, c.userAgent)
+
+	ts := tokenSourceFromContext(ctx)
+	if ts != nil {
+		if stale, ok := bearerToken(headers); ok {
+			token, err := ts.AccessToken(withHTTPClient(ctx, c.client))
+			if err != nil {
+				return nil, err
+			}
+
+			if token != stale {
+				headers = withBearerToken(headers, token)
+			}
 		}
 	}
 
-	for name, value := range fields {
-		if err := writer.WriteField(name, value); err != nil {
-			return nil, err
+	attempt := 1
+	refreshed := false
+	for {
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx); err != nil {
+				return nil, err
+			}
 		}
-	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url.String(), body)
-	if err != nil {
-		return nil, err
-	}
+		if c.breaker != nil {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 
-	request.Header.Add("content-type", "multipart/form-data")
-	for k, v := range headers {
-		request.Header.Add(k, v[0])
-	}
+			if !c.breaker.allow() {
+				return nil, ErrCircuitOpen
+			}
+		}
 
-	response, err := c.client.Do(request)
-	if err != nil {
-		return nil, err
-	}
+		var info RequestInfo
+		if c.observer != nil {
+			info = RequestInfo{
+				Method:  method,
+				Path:    pathTemplate(url),
+				Attempt: attempt,
+			}
+			c.observer.RequestStarted(ctx, info)
+		}
 
-	defer response.Body.Close()
+		start := time.Now()
+		respObj, err := c.send(ctx, method, url, headers, body)
+		duration := time.Since(start)
+		if respObj != nil {
+			respObj.RequestID = requestID
+		}
+		if c.logger != nil {
+			c.logger.log(ctx, method, url, headers, body, respObj, err, duration)
+		}
+		if c.breaker != nil {
+			c.breaker.record(respObj, err)
+		}
+		if c.observer != nil {
+			result := RequestResult{
+				Duration: duration,
+				Err:      err,
+			}
+			if respObj != nil {
+				result.StatusCode = respObj.StatusCode
+			}
+			c.observer.RequestFinished(ctx, info, result)
+		}
 
-	respObj := &Response{
-		StatusCode: response.StatusCode,
-		Headers:    response.Header,
-	}
+		// the token may have been revoked or expired early, so refresh it and try again
+		if ts != nil && !refreshed && err == nil && respObj.StatusCode == http.StatusUnauthorized {
+			if stale, ok := bearerToken(headers); ok {
+				token, err := ts.Refresh(withHTTPClient(ctx, c.client), stale)
+				if err != nil {
+					return nil, err
+				}
+
+				headers = withBearerToken(headers, token)
+				refreshed = true
+				continue
+			}
+		}
 
-	if response.Body != nil {
-		resBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("unable to extract the body")
+		if c.retry == nil || attempt >= c.retry.MaxAttempts || !c.retry.shouldRetry(method, respObj, err) {
+			return respObj, err
 		}
 
-		respObj.Body = resBody
-	}
+		wait := c.retry.backoff(attempt, respObj)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
 
-	return respObj, nil
+		attempt++
+	}
 }
 
-// Put makes a HTTP PUT call and returns the response
-func (c *defaultClientx) Put(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*Response, error) {
-	bodyReader := bytes.NewReader([]byte(body))
-	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url.String(), bodyReader)
+// send makes a single HTTP call and reads the full response.
+func (c *defaultClientx) send(ctx context.Context, method string, url *url.URL, headers http.Header, body []byte) (*Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url.String(), bodyReader)
 	if err != nil {
 		return nil, err
 	}
@@ -183,22 +306,41 @@ func (c *defaultClientx) Put(ctx context.Context, url *url.URL, headers http.Hea
 	}
 
 	if response.Body != nil {
-		resBody, err := io.ReadAll(response.Body)
+		// the transport asks for gzip and decodes it transparently unless the caller
+		// set Accept-Encoding, in which case the body is decoded here
+		var bodyReader io.Reader = response.Body
+		if !response.Uncompressed && response.ContentLength != 0 && strings.EqualFold(response.Header.Get("Content-Encoding"), "gzip") {
+			gz, err := gzip.NewReader(response.Body)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decompress the body; err=%w", err)
+			}
+			defer gz.Close()
+
+			bodyReader = gz
+			respObj.Headers.Del("Content-Encoding")
+			respObj.Headers.Del("Content-Length")
+		}
+
+		// one byte past the limit is read to tell a body of exactly the maximum size
+		// from a larger one
+		resBody, err := io.ReadAll(io.LimitReader(bodyReader, c.maxBody+1))
 		if err != nil {
 			return nil, fmt.Errorf("unable to extract the body")
 		}
 
+		if int64(len(resBody)) > c.maxBody {
+			return nil, fmt.Errorf("%w; limit=%d bytes", ErrResponseTooLarge, c.maxBody)
+		}
+
 		respObj.Body = resBody
 	}
 
 	return respObj, nil
 }
 
-// PutMultipart makes a HTTP PUT call with content-type set to multipart/form-data and returns the response
-func (c *defaultClientx) PutMultipart(ctx context.Context, url *url.URL, headers http.Header, files map[string][]byte, fields map[string]string) (*Response, error) {
+func multipartBody(files map[string][]byte, fields map[string]string) ([]byte, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
-	defer writer.Close()
 
 	for fileName, data := range files {
 		part, err := writer.CreateFormFile(fileName, fileName)
@@ -218,109 +360,23 @@ func (c *defaultClientx) PutMultipart(ctx context.Context, url *url.URL, headers
 		}
 	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url.String(), body)
-	if err != nil {
+	if err := writer.Close(); err != nil {
 		return nil, err
 	}
 
-	request.Header.Add("content-type", "multipart/form-data")
-	for k, v := range headers {
-		request.Header.Add(k, v[0])
-	}
-
-	response, err := c.client.Do(request)
-	if err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	respObj := &Response{
-		StatusCode: response.StatusCode,
-		Headers:    response.Header,
-	}
-
-	if response.Body != nil {
-		resBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("unable to extract the body")
-		}
-
-		respObj.Body = resBody
-	}
-
-	return respObj, nil
+	return body.Bytes(), nil
 }
 
-// Patch makes a HTTP PATCH call and returns the response
-func (c *defaultClientx) Patch(ctx context.Context, url *url.URL, headers http.Header, body []byte) (*Response, error) {
-	bodyReader := bytes.NewReader([]byte(body))
-	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, url.String(), bodyReader)
-	if err != nil {
-		return nil, err
+func multipartHeaders(headers http.Header) http.Header {
+	h := http.Header{
+		"content-type": []string{"multipart/form-data"},
 	}
 
 	for k, v := range headers {
-		request.Header.Add(k, v[0])
+		h[k] = v
 	}
 
-	response, err := c.client.Do(request)
-	if err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	respObj := &Response{
-		StatusCode: response.StatusCode,
-		Headers:    response.Header,
-	}
-
-	if response.Body != nil {
-		resBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("unable to extract the body")
-		}
-
-		respObj.Body = resBody
-	}
-
-	return respObj, nil
-}
-
-// Delete makes a HTTP DELETE call and returns the response
-func (c *defaultClientx) Delete(ctx context.Context, url *url.URL, headers http.Header) (*Response, error) {
-	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, url.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	for k, v := range headers {
-		request.Header.Add(k, v[0])
-	}
-
-	response, err := c.client.Do(request)
-	if err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	respObj := &Response{
-		StatusCode: response.StatusCode,
-		Headers:    response.Header,
-	}
-
-	if response.Body != nil {
-		resBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("unable to extract the body")
-		}
-
-		respObj.Body = resBody
-	}
-
-	return respObj, nil
+	return h
 }
 
 func noRedirects(req *http.Request, via []*http.Request) error {
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newTestServer starts a server with the handler and returns its URL. The server
+// is closed when the test ends.
+func newTestServer(t *testing.T, handler http.HandlerFunc) *url.URL {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("unable to parse the server URL; err=%v", err)
+	}
+
+	return u
+}
+
+// slowHandler responds after the delay, or gives up once the request is cancelled.
+func slowHandler(delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	}
+}
+
+func TestTimeoutAppliesWithoutDeadline(t *testing.T) {
+	u := newTestServer(t, slowHandler(5*time.Second))
+	client := NewDefaultClient(WithTimeout(50 * time.Millisecond))
+
+	start := time.Now()
+	_, err := client.Get(context.Background(), u, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get() err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get() returned after %s, want about 50ms", elapsed)
+	}
+}
+
+func TestTimeoutKeepsCallerDeadline(t *testing.T) {
+	u := newTestServer(t, slowHandler(100*time.Millisecond))
+	client := NewDefaultClient(WithTimeout(10 * time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := client.Get(ctx, u, nil)
+	if err != nil {
+		t.Fatalf("Get() err = %v, want nil", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Get() status = %d, want %d", response.StatusCode, http.StatusOK)
+	}
+}
+
+func TestGzipResponseIsDecoded(t *testing.T) {
+	const body = `{"totalResults":1,"Resources":[{"id":"g1"}]}`
+	u := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+
+		buf := &bytes.Buffer{}
+		gz := gzip.NewWriter(buf)
+		_, _ = gz.Write([]byte(body))
+		_ = gz.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(buf.Bytes())
+	})
+
+	tests := map[string]http.Header{
+		"decoded by the transport": nil,
+		"decoded by the client":    {"Accept-Encoding": []string{"gzip"}},
+	}
+
+	for name, headers := range tests {
+		t.Run(name, func(t *testing.T) {
+			response, err := NewDefaultClient().Get(context.Background(), u, headers)
+			if err != nil {
+				t.Fatalf("Get() err = %v, want nil", err)
+			}
+
+			if string(response.Body) != body {
+				t.Errorf("Get() body = %q, want %q", response.Body, body)
+			}
+
+			if response.Headers.Get("Content-Encoding") != "" {
+				t.Errorf("Content-Encoding = %q, want it removed", response.Headers.Get("Content-Encoding"))
+			}
+		})
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	var requests atomic.Int32
+	u := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(strings.Repeat("a", 11)))
+	})
+
+	client := NewDefaultClient(WithMaxResponseSize(10), WithRetry(RetryPolicy{MaxAttempts: 3}))
+	_, err := client.Get(context.Background(), u, nil)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Get() err = %v, want %v", err, ErrResponseTooLarge)
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("sent %d requests, want 1 as an over-limit body is not retried", n)
+	}
+}
+
+func TestMaxResponseSizeAllowsBodyAtLimit(t *testing.T) {
+	u := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 10)))
+	})
+
+	response, err := NewDefaultClient(WithMaxResponseSize(10)).Get(context.Background(), u, nil)
+	if err != nil {
+		t.Fatalf("Get() err = %v, want nil", err)
+	}
+
+	if len(response.Body) != 10 {
+		t.Errorf("Get() body has %d bytes, want 10", len(response.Body))
+	}
+}
+
+// fakeTokenSource returns fresh for every stale token and records whether the
+// context carried an HTTP client for oauth2.
+type fakeTokenSource struct {
+	withClient atomic.Int32
+}
+
+func (ts *fakeTokenSource) AccessToken(ctx context.Context) (string, error) {
+	return ts.token(ctx)
+}
+
+func (ts *fakeTokenSource) Refresh(ctx context.Context, staleToken string) (string, error) {
+	return ts.token(ctx)
+}
+
+func (ts *fakeTokenSource) token(ctx context.Context) (string, error) {
+	if _, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		ts.withClient.Add(1)
+	}
+
+	return "fresh", nil
+}
+
+func TestTokenSourceGetsHTTPClient(t *testing.T) {
+	var attempts atomic.Int32
+	u := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ts := &fakeTokenSource{}
+	headers := http.Header{
+		"Authorization": []string{"Bearer stale"},
+	}
+	response, err := NewDefaultClient().Get(ContextWithTokenSource(context.Background(), ts), u, headers)
+	if err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("Get() = %v, %v; want 200", response, err)
+	}
+
+	// once before the request and once after the 401
+	if got := ts.withClient.Load(); got != 2 {
+		t.Errorf("token source got the HTTP client %d times, want 2", got)
+	}
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"net/http"
+)
+
+type defaultHeadersKey struct{}
+
+// ContextWithHeaders returns a context whose requests are sent with the headers,
+// such as a trace header, in addition to their own. Headers already attached to
+// the context are kept unless they are set again.
+//
+// Headers are merged in order of precedence: those set on the call override
+// those of the context, which override those of WithDefaultHeaders. A header is
+// replaced as a whole, never combined with the values of a lower layer.
+func ContextWithHeaders(ctx context.Context, headers http.Header) context.Context {
+	merged := http.Header{}
+	for k, v := range HeadersFromContext(ctx) {
+		merged[k] = v
+	}
+
+	for k, v := range headers {
+		merged[http.CanonicalHeaderKey(k)] = v
+	}
+
+	return context.WithValue(ctx, defaultHeadersKey{}, merged)
+}
+
+// HeadersFromContext returns the headers attached with ContextWithHeaders, or nil
+// if there are none.
+func HeadersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(defaultHeadersKey{}).(http.Header)
+	return headers
+}
+
+// WithDefaultHeaders sets headers sent with every request made by the client. See
+// ContextWithHeaders for how they are merged with the headers of each call.
+func WithDefaultHeaders(headers http.Header) ClientOption {
+	return func(c *defaultClientx) {
+		c.headers = http.Header{}
+		for k, v := range headers {
+			c.headers[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+}
+
+// withDefaultHeaders returns the headers with those of the client and the context
+// added, unless the caller set them.
+func withDefaultHeaders(ctx context.Context, headers http.Header, clientHeaders http.Header) http.Header {
+	contextHeaders := HeadersFromContext(ctx)
+	if len(contextHeaders) == 0 && len(clientHeaders) == 0 {
+		return headers
+	}
+
+	h := make(http.Header, len(headers)+len(contextHeaders)+len(clientHeaders))
+	for _, defaults := range []http.Header{clientHeaders, contextHeaders} {
+		for k, v := range defaults {
+			h[k] = v
+		}
+	}
+
+	for k := range headers {
+		delete(h, http.CanonicalHeaderKey(k))
+	}
+
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	return h
+}
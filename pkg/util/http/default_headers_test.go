@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestDefaultHeadersPrecedence(t *testing.T) {
+	received := make(chan http.Header, 1)
+	u := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	})
+
+	client := NewDefaultClient(WithDefaultHeaders(http.Header{
+		"x-trace":       []string{"client"},
+		"X-On-Behalf":   []string{"client"},
+		"X-Client-Only": []string{"client"},
+	}))
+
+	ctx := ContextWithHeaders(context.Background(), http.Header{
+		"X-Trace":     []string{"first"},
+		"X-Context":   []string{"context"},
+		"x-on-behalf": []string{"context"},
+	})
+	ctx = ContextWithHeaders(ctx, http.Header{"X-Trace": []string{"context"}})
+
+	if _, err := client.Get(ctx, u, http.Header{"x-on-behalf": []string{"call"}}); err != nil {
+		t.Fatalf("Get() err = %v, want nil", err)
+	}
+
+	headers := <-received
+	want := map[string]string{
+		"X-Client-Only": "client",
+		"X-Context":     "context",
+		"X-Trace":       "context",
+		"X-On-Behalf":   "call",
+	}
+	for name, value := range want {
+		if got := headers.Values(name); len(got) != 1 || got[0] != value {
+			t.Errorf("%s = %v, want [%s]", name, got, value)
+		}
+	}
+}
+
+func TestDefaultHeadersDoNotChangeCallerHeaders(t *testing.T) {
+	callHeaders := http.Header{"Accept": []string{"application/scim+json"}}
+	merged := withDefaultHeaders(ContextWithHeaders(context.Background(), http.Header{"X-Trace": []string{"t"}}), callHeaders, nil)
+
+	if merged.Get("X-Trace") != "t" {
+		t.Errorf("X-Trace = %q, want t", merged.Get("X-Trace"))
+	}
+
+	if len(callHeaders) != 1 {
+		t.Errorf("the caller's headers were changed: %v", callHeaders)
+	}
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+const (
+	defaultMaxLoggedBodySize = 4096
+)
+
+// RequestLogOptions controls what is logged for each request.
+type RequestLogOptions struct {
+	// LogBody includes the request and response bodies in the log entry.
+	LogBody bool
+	// MaxBodySize caps the number of bytes of each body that are logged.
+	// Defaults to 4KB.
+	MaxBodySize int
+}
+
+type requestLogger struct {
+	logger  func(ctx context.Context) *logx.Logger
+	options RequestLogOptions
+}
+
+// WithRequestLogging logs the method, URL, status code and duration of every request
+// at debug level. The logger is resolved from the request context, which allows
+// output to go to the same place as the rest of the command, for example using
+// config.GetLogger. The Authorization header is never logged.
+func WithRequestLogging(logger func(ctx context.Context) *logx.Logger, options RequestLogOptions) ClientOption {
+	return func(c *defaultClientx) {
+		if logger == nil {
+			return
+		}
+
+		if options.MaxBodySize <= 0 {
+			options.MaxBodySize = defaultMaxLoggedBodySize
+		}
+
+		c.logger = &requestLogger{
+			logger:  logger,
+			options: options,
+		}
+	}
+}
+
+func (l *requestLogger) log(ctx context.Context, method string, u *url.URL, headers http.Header, body []byte,
+	response *Response, err error, duration time.Duration) {
+
+	logger := l.logger(ctx)
+	if logger == nil {
+		return
+	}
+
+	status := 0
+	if response != nil {
+		status = response.StatusCode
+	}
+
+	args := []any{
+		"method", method,
+		"url", logx.Redact(u.String()),
+		"status", status,
+		"duration", duration.String(),
+		"headers", redactHeaders(headers),
+	}
+
+	if l.options.LogBody {
+		args = append(args, "requestBody", l.truncate(body))
+		if response != nil {
+			args = append(args, "responseBody", l.truncate(response.Body))
+		}
+	}
+
+	if err != nil {
+		args = append(args, "err", logx.Redact(err.Error()))
+	}
+
+	logger.Debug("http request", args...)
+}
+
+func (l *requestLogger) truncate(body []byte) string {
+	if len(body) > l.options.MaxBodySize {
+		return logx.Redact(string(body[:l.options.MaxBodySize])) + "...(truncated)"
+	}
+
+	return logx.Redact(string(body))
+}
+
+func redactHeaders(headers http.Header) http.Header {
+	redacted := http.Header{}
+	for k, v := range headers {
+		if http.CanonicalHeaderKey(k) == "Authorization" {
+			redacted[k] = []string{"REDACTED"}
+			continue
+		}
+
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			values = append(values, logx.Redact(value))
+		}
+		redacted[k] = values
+	}
+
+	return redacted
+}
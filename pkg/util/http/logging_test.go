@@ -0,0 +1,42 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+func TestRequestLoggingRedactsTokens(t *testing.T) {
+	const secret = "c2VjcmV0LXRva2Vu"
+	u := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"detail":"invalid header Authorization: Bearer ` + secret + `"}`))
+	})
+
+	buf := &bytes.Buffer{}
+	logger := logx.NewLoggerWithWriter("test", slog.LevelDebug, buf)
+	client := NewDefaultClient(WithRequestLogging(func(ctx context.Context) *logx.Logger {
+		return logger
+	}, RequestLogOptions{LogBody: true}))
+
+	headers := http.Header{
+		"Authorization": []string{"Bearer " + secret},
+		"X-Forwarded":   []string{"bearer " + secret},
+	}
+	if _, err := client.Post(context.Background(), u, headers, []byte(`{"access_token":"`+secret+`"}`)); err != nil {
+		t.Fatalf("Post() err = %v, want nil", err)
+	}
+
+	if buf.Len() == 0 {
+		t.Fatal("the request was not logged")
+	}
+
+	if strings.Contains(buf.String(), secret) {
+		t.Errorf("the log contains the token: %s", buf.String())
+	}
+}
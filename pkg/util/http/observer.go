@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Observer is notified of every request the client sends, including each retry,
+// so that callers can record metrics such as request counts, latencies and error
+// rates. The callbacks are made synchronously and must be safe for concurrent use.
+type Observer interface {
+	// RequestStarted is called before the request is sent.
+	RequestStarted(ctx context.Context, info RequestInfo)
+	// RequestFinished is called once the response has been read or the request
+	// has failed.
+	RequestFinished(ctx context.Context, info RequestInfo, result RequestResult)
+}
+
+// RequestInfo describes a request for an Observer.
+type RequestInfo struct {
+	Method string
+	// Path is the URL path with the resource IDs replaced by {id}, such as
+	// /v2.0/Groups/{id}, so that it can be used as a metric label.
+	Path string
+	// Attempt is 1 for the first attempt and increases with each retry.
+	Attempt int
+}
+
+// RequestResult describes the outcome of a request for an Observer.
+type RequestResult struct {
+	// StatusCode is 0 if no response was received.
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// WithObserver notifies the observer of every request. Without an observer, no
+// request information is collected.
+func WithObserver(observer Observer) ClientOption {
+	return func(c *defaultClientx) {
+		c.observer = observer
+	}
+}
+
+// pathTemplate returns the path of the URL with the segments that look like
+// resource IDs replaced by {id}.
+func pathTemplate(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if looksLikeID(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// looksLikeID reports whether the path segment is an identifier rather than a
+// resource name or API version. Verify IDs are long and contain digits, while
+// resource names and versions, such as Groups or v2.0, do not.
+func looksLikeID(segment string) bool {
+	if len(segment) < 8 {
+		return false
+	}
+
+	for _, r := range segment {
+		if unicode.IsDigit(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// WithProxy sends all requests through the proxy, overriding the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables, which are honoured otherwise.
+// The proxy URL may use the http, https or socks5 scheme, and credentials for an
+// authenticated proxy can be included as user:pass in the URL.
+func WithProxy(proxyURL *url.URL) ClientOption {
+	return func(c *defaultClientx) {
+		c.proxy = proxyURL
+	}
+}
+
+// proxyFunc returns the proxy selector used by the transport.
+func (c *defaultClientx) proxyFunc() func(*http.Request) (*url.URL, error) {
+	if c.proxy == nil {
+		return http.ProxyFromEnvironment
+	}
+
+	return http.ProxyURL(c.proxy)
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that refills at a fixed rate up to its burst size.
+type rateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+}
+
+// WithRateLimit caps the client to requestsPerSecond, allowing bursts of up to
+// burst requests. Requests block until a token is available or the context is done.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *defaultClientx) {
+		if requestsPerSecond <= 0 {
+			return
+		}
+
+		if burst < 1 {
+			burst = 1
+		}
+
+		c.limiter = &rateLimiter{
+			rate:     requestsPerSecond,
+			burst:    float64(burst),
+			tokens:   float64(burst),
+			lastFill: time.Now(),
+		}
+	}
+}
+
+// wait blocks until a token is taken from the bucket or the context is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and otherwise returns the time until
+// the next token is due.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.lastFill).Seconds()*l.rate)
+	l.lastFill = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
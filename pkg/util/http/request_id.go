@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader is the header that carries the request ID.
+	RequestIDHeader = "X-Request-ID"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context whose requests are sent with the ID in
+// the X-Request-ID header, so that they can be correlated with the audit logs of
+// the tenant. Without it, a random ID is generated for each request.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with ContextWithRequestID, or an
+// empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// withRequestID returns the headers with the request ID set, along with the ID.
+// An ID already set by the caller is kept.
+func withRequestID(ctx context.Context, headers http.Header) (http.Header, string) {
+	for k, v := range headers {
+		if http.CanonicalHeaderKey(k) == RequestIDHeader && len(v) > 0 {
+			return headers, v[0]
+		}
+	}
+
+	id := RequestIDFromContext(ctx)
+	if len(id) == 0 {
+		id = uuid.NewString()
+	}
+
+	h := make(http.Header, len(headers)+1)
+	for k, v := range headers {
+		h[k] = v
+	}
+	h[RequestIDHeader] = []string{id}
+
+	return h, id
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the longest wait before the first retry. It doubles on every
+	// subsequent retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, including waits requested by
+	// the server through the Retry-After header.
+	MaxBackoff time.Duration
+	// Rand returns a random duration in [0, n) and is used to add jitter to the
+	// backoff. It defaults to math/rand and can be replaced to make the backoff
+	// deterministic.
+	Rand func(n int64) int64
+}
+
+// WithRetry enables retries with exponential backoff for 429 and 5xx responses
+// and for connection failures. Full jitter is applied to the backoff so that
+// requests that fail together do not retry together.
+//
+// POST and PATCH requests are not idempotent, so they are only retried when the
+// server has clearly not processed the request; that is, when the connection could
+// not be established or the server responded with 429.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *defaultClientx) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+
+		if policy.InitialBackoff <= 0 {
+			policy.InitialBackoff = 500 * time.Millisecond
+		}
+
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = 30 * time.Second
+		}
+
+		if policy.Rand == nil {
+			policy.Rand = rand.Int63n
+		}
+
+		c.retry = &policy
+	}
+}
+
+func (p *RetryPolicy) shouldRetry(method string, response *Response, err error) bool {
+	idempotent := method != http.MethodPost && method != http.MethodPatch
+	if err != nil {
+		// the tenant would send the same body again
+		if errors.Is(err, ErrResponseTooLarge) {
+			return false
+		}
+
+		if idempotent {
+			return true
+		}
+
+		// the request was never sent if the connection could not be established
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return idempotent && response.StatusCode >= http.StatusInternalServerError
+}
+
+func (p *RetryPolicy) backoff(attempt int, response *Response) time.Duration {
+	if response != nil && response.StatusCode == http.StatusTooManyRequests {
+		if wait, ok := retryAfter(response.Headers.Get("Retry-After")); ok {
+			return min(wait, p.MaxBackoff)
+		}
+	}
+
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+
+	// full jitter picks a wait anywhere between zero and the exponential backoff
+	wait = min(wait, p.MaxBackoff)
+	return time.Duration(p.Rand(int64(wait) + 1))
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds
+// or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+
+	return 0, false
+}
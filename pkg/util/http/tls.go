@@ -0,0 +1,46 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/ibm-security-verify/verifyctl/x/logx"
+)
+
+// WithRootCAs trusts the certificates in the pool, instead of the system pool, when
+// verifying the server. This is needed when the tenant is fronted by a gateway that
+// uses a certificate issued by a private CA.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *defaultClientx) {
+		c.tlsConfig().RootCAs = pool
+	}
+}
+
+// WithInsecureSkipVerify disables verification of the server certificate when skip
+// is true. This makes connections vulnerable to interception and should only be used
+// for testing. A warning is written to the logger, such as the one returned by
+// config.GetLogger, when the client is built.
+func WithInsecureSkipVerify(skip bool, logger *logx.Logger) ClientOption {
+	return func(c *defaultClientx) {
+		if !skip {
+			return
+		}
+
+		if logger != nil {
+			logger.Warnf("TLS certificate verification is disabled; connections to the tenant are not secure")
+		}
+		c.tlsConfig().InsecureSkipVerify = true
+	}
+}
+
+// tlsConfig returns the TLS config of the client, creating one with strict defaults
+// if needed.
+func (c *defaultClientx) tlsConfig() *tls.Config {
+	if c.tls == nil {
+		c.tls = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return c.tls
+}
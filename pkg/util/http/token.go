@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+type tokenSourceKey struct{}
+
+// TokenSource supplies the bearer token sent with each request. The context passed
+// to it carries the *http.Client of the client under oauth2.HTTPClient, so that
+// token requests use the same TLS and proxy settings as the API requests.
+type TokenSource interface {
+	// AccessToken returns a valid access token, refreshing it if it is about to expire.
+	AccessToken(ctx context.Context) (string, error)
+
+	// Refresh returns a new access token to replace staleToken, which the server
+	// has rejected.
+	Refresh(ctx context.Context, staleToken string) (string, error)
+}
+
+// ContextWithTokenSource returns a context that makes the client keep the bearer
+// token in the Authorization header fresh. The token is refreshed before it expires
+// and when the server responds with 401, in which case the request is sent once more.
+func ContextWithTokenSource(ctx context.Context, ts TokenSource) context.Context {
+	return context.WithValue(ctx, tokenSourceKey{}, ts)
+}
+
+// withHTTPClient returns a context that makes oauth2 send token requests with the
+// client.
+func withHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
+
+func tokenSourceFromContext(ctx context.Context) TokenSource {
+	ts, _ := ctx.Value(tokenSourceKey{}).(TokenSource)
+	return ts
+}
+
+// bearerToken returns the token in the Authorization header, if there is one.
+func bearerToken(headers http.Header) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, "Authorization") && len(v) > 0 {
+			return strings.CutPrefix(v[0], "Bearer ")
+		}
+	}
+
+	return "", false
+}
+
+// withBearerToken returns a copy of the headers with the Authorization header
+// replaced by the token.
+func withBearerToken(headers http.Header, token string) http.Header {
+	h := http.Header{}
+	for k, v := range headers {
+		if strings.EqualFold(k, "Authorization") {
+			continue
+		}
+
+		h[k] = v
+	}
+
+	h["Authorization"] = []string{"Bearer " + token}
+	return h
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+// Version is the verifyctl version reported in the default User-Agent. It is set
+// at build time with
+//
+//	-ldflags "-X github.com/ibm-security-verify/verifyctl/pkg/util/http.Version=<version>"
+var Version = "dev"
+
+// DefaultUserAgent returns the User-Agent sent when none is configured, such as
+// "verifyctl/1.0.0 (linux/amd64)".
+func DefaultUserAgent() string {
+	return fmt.Sprintf("verifyctl/%s (%s/%s)", Version, runtime.GOOS, runtime.GOARCH)
+}
+
+// WithUserAgent sets the User-Agent sent with every request, unless the caller sets
+// the header on the request. An empty value restores the default.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *defaultClientx) {
+		c.userAgent = userAgent
+	}
+}
+
+// withUserAgent returns the headers with the User-Agent set. A User-Agent already
+// set by the caller is kept.
+func withUserAgent(headers http.Header, userAgent string) http.Header {
+	for k, v := range headers {
+		if http.CanonicalHeaderKey(k) == "User-Agent" && len(v) > 0 {
+			return headers
+		}
+	}
+
+	h := make(http.Header, len(headers)+1)
+	for k, v := range headers {
+		h[k] = v
+	}
+	h.Set("User-Agent", userAgent)
+
+	return h
+}
@@ -11,19 +11,19 @@ type Logger struct {
 }
 
 func (l *Logger) Errorf(str string, args ...interface{}) {
-	l.Error(fmt.Sprintf(str, args...))
+	l.Error(Redact(fmt.Sprintf(str, args...)))
 }
 
 func (l *Logger) Infof(str string, args ...interface{}) {
-	l.Info(fmt.Sprintf(str, args...))
+	l.Info(Redact(fmt.Sprintf(str, args...)))
 }
 
 func (l *Logger) Warnf(str string, args ...interface{}) {
-	l.Warn(fmt.Sprintf(str, args...))
+	l.Warn(Redact(fmt.Sprintf(str, args...)))
 }
 
 func (l *Logger) Debugf(str string, args ...interface{}) {
-	l.Debug(fmt.Sprintf(str, args...))
+	l.Debug(Redact(fmt.Sprintf(str, args...)))
 }
 
 // NewLoggerWithWriter returns a new logger instance with the
@@ -0,0 +1,17 @@
+package logx
+
+import (
+	"regexp"
+)
+
+var (
+	bearerTokenPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+	tokenFieldPattern  = regexp.MustCompile(`(?i)("(?:access_token|refresh_token|id_token|client_secret)"\s*:\s*")[^"]*(")`)
+)
+
+// Redact masks bearer tokens and token fields in JSON documents so that they
+// can be safely written to logs and error messages.
+func Redact(s string) string {
+	s = bearerTokenPattern.ReplaceAllString(s, "${1}REDACTED")
+	return tokenFieldPattern.ReplaceAllString(s, "${1}REDACTED${2}")
+}
@@ -0,0 +1,55 @@
+package logx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	const secret = "eyJhbGciOiJSUzI1NiJ9.c2VjcmV0.c2lnbmF0dXJl"
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "authorization header",
+			input: "Authorization: Bearer " + secret,
+		},
+		{
+			name:  "lower case bearer",
+			input: "bearer " + secret,
+		},
+		{
+			name:  "access token field",
+			input: `{"access_token":"` + secret + `","token_type":"Bearer"}`,
+		},
+		{
+			name:  "refresh token field with spaces",
+			input: `{"refresh_token" : "` + secret + `"}`,
+		},
+		{
+			name:  "client secret field",
+			input: `{"client_secret":"` + secret + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redact(tt.input)
+			if strings.Contains(got, secret) {
+				t.Errorf("Redact(%q) = %q, which contains the secret", tt.input, got)
+			}
+
+			if !strings.Contains(got, "REDACTED") {
+				t.Errorf("Redact(%q) = %q, want the secret replaced with REDACTED", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestRedactKeepsOtherText(t *testing.T) {
+	input := `{"displayName":"Sales","members":[]}`
+	if got := Redact(input); got != input {
+		t.Errorf("Redact(%q) = %q, want it unchanged", input, got)
+	}
+}